@@ -1,7 +1,5 @@
 package revoltgo
 
-import "log"
-
 //go:generate msgp -tests=false -io=false
 
 type ChannelType string
@@ -84,7 +82,7 @@ func (c *Channel) update(data PartialChannel) {
 	}
 }
 
-func (c *Channel) clear(fields []string) {
+func (c *Channel) clear(fields []string) (unknown []string) {
 	for _, field := range fields {
 		switch field {
 		case "Icon":
@@ -92,9 +90,11 @@ func (c *Channel) clear(fields []string) {
 		case "Description":
 			c.Description = nil
 		default:
-			log.Printf("Channel.clear(): unknown field %s", field)
+			unknown = append(unknown, field)
 		}
 	}
+
+	return unknown
 }
 
 type PartialChannel struct {
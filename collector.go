@@ -0,0 +1,158 @@
+package revoltgo
+
+import (
+	"context"
+	"sync"
+)
+
+// WaitFor waits for the next event of type T for which predicate returns true; a nil predicate matches any event.
+// It returns ctx.Err() if ctx ends first, so use context.WithTimeout to give up after a while:
+//
+//	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+//	defer cancel()
+//
+//	reply, err := revoltgo.WaitFor(ctx, session, func(e *revoltgo.EventMessage) bool {
+//		return e.Channel == prompt.Channel && e.Author == prompt.Author
+//	})
+//
+// The predicate is called from event dispatch, like any handler. The handler is removed before WaitFor returns.
+func WaitFor[T any](ctx context.Context, s *Session, predicate func(T) bool) (T, error) {
+	matched := make(chan T, 1)
+
+	remove := AddHandler(s, func(_ *Session, e T) {
+		if predicate != nil && !predicate(e) {
+			return
+		}
+
+		// Only the first match is kept; events dispatched concurrently may also match
+		select {
+		case matched <- e:
+		default:
+		}
+	})
+	defer remove()
+
+	select {
+	case e := <-matched:
+		return e, nil
+	case <-ctx.Done():
+		var zero T
+		return zero, ctx.Err()
+	}
+}
+
+// Collector gathers events of type T, until it is stopped, its context ends, or it reaches its limit.
+// Its handler is removed as soon as it is done.
+//
+//	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+//	defer cancel()
+//
+//	votes := revoltgo.NewCollector(ctx, session, func(e *revoltgo.EventMessageReact) bool {
+//		return e.ID == poll.ID
+//	}, 0)
+//
+//	for _, vote := range votes.Wait() {
+//		// ...
+//	}
+type Collector[T any] struct {
+	filter func(T) bool
+	limit  int
+	remove func()
+
+	mu        sync.Mutex
+	collected []T
+	stopped   bool
+	err       error
+
+	done     chan struct{}
+	stopOnce sync.Once
+}
+
+// NewCollector starts collecting events of type T for which filter returns true; a nil filter matches any event.
+// The filter is called from event dispatch, so it may also be used to react to every collected event.
+// A limit of zero or less collects until ctx ends or Stop is called; with context.Background, Stop must be called.
+func NewCollector[T any](ctx context.Context, s *Session, filter func(T) bool, limit int) *Collector[T] {
+	c := &Collector[T]{
+		filter: filter,
+		limit:  limit,
+		done:   make(chan struct{}),
+	}
+
+	c.remove = AddHandler(s, c.collect)
+
+	go func() {
+		select {
+		case <-ctx.Done():
+			c.stop(ctx.Err())
+		case <-c.done:
+		}
+	}()
+
+	return c
+}
+
+func (c *Collector[T]) collect(_ *Session, e T) {
+	if c.filter != nil && !c.filter(e) {
+		return
+	}
+
+	// Concurrent handlers may all get here before the one that fills the Collector stops it
+	c.mu.Lock()
+	if c.stopped || c.limit > 0 && len(c.collected) >= c.limit {
+		c.mu.Unlock()
+		return
+	}
+
+	c.collected = append(c.collected, e)
+	full := c.limit > 0 && len(c.collected) >= c.limit
+	c.mu.Unlock()
+
+	if full {
+		c.stop(nil)
+	}
+}
+
+func (c *Collector[T]) stop(err error) {
+	c.stopOnce.Do(func() {
+		c.mu.Lock()
+		c.stopped = true
+		c.err = err
+		c.mu.Unlock()
+
+		c.remove()
+		close(c.done)
+	})
+}
+
+// Stop stops collecting. Events collected so far are kept.
+func (c *Collector[T]) Stop() {
+	c.stop(nil)
+}
+
+// Done is closed once the Collector has stopped collecting.
+func (c *Collector[T]) Done() <-chan struct{} {
+	return c.done
+}
+
+// Wait blocks until the Collector has stopped, and returns the collected events.
+func (c *Collector[T]) Wait() []T {
+	<-c.done
+	return c.Collected()
+}
+
+// Collected returns a copy of the events collected so far, oldest first.
+func (c *Collector[T]) Collected() []T {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return append([]T(nil), c.collected...)
+}
+
+// Err returns the error of the context that stopped the Collector, e.g. context.DeadlineExceeded.
+// It is nil while collecting, and if the Collector was stopped by Stop or by reaching its limit.
+func (c *Collector[T]) Err() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.err
+}
@@ -0,0 +1,80 @@
+package revoltgo_test
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/sentinelb51/revoltgo"
+	"github.com/sentinelb51/revoltgo/revolttest"
+)
+
+// TestCollectorLimit pushes more events than the limit, handled concurrently; the limit must never be exceeded.
+func TestCollectorLimit(t *testing.T) {
+	const limit = 5
+
+	server := revolttest.NewServer()
+	defer server.Close()
+
+	session := server.Session("token")
+	session.DispatchMode = revoltgo.DispatchAsync
+	open(t, session)
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	collector := revoltgo.NewCollector(ctx, session, func(e *revoltgo.EventMessage) bool {
+		return e.Channel == "general"
+	}, limit)
+
+	for i := range 50 {
+		channel := "general"
+		if i%2 == 1 {
+			channel = "random"
+		}
+
+		if err := server.Push(&revoltgo.EventMessage{Message: revoltgo.Message{ID: strconv.Itoa(i), Channel: channel}}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	collected := collector.Wait()
+	if len(collected) != limit {
+		t.Fatalf("collected %d events, want %d", len(collected), limit)
+	}
+
+	for _, e := range collected {
+		if e.Channel != "general" {
+			t.Fatalf("collected an event of %s", e.Channel)
+		}
+	}
+
+	if err := collector.Err(); err != nil {
+		t.Fatalf("collector stopped with %v, want nil once full", err)
+	}
+}
+
+func TestCollectorContext(t *testing.T) {
+	server := revolttest.NewServer()
+	defer server.Close()
+
+	session := server.Session("token")
+	open(t, session)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	collector := revoltgo.NewCollector[*revoltgo.EventMessage](ctx, session, nil, 0)
+
+	if err := server.Push(&revoltgo.EventMessage{Message: revoltgo.Message{ID: "1", Channel: "general"}}); err != nil {
+		t.Fatal(err)
+	}
+
+	wait(t, collector.Done(), "the context to end")
+
+	if err := collector.Err(); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("collector stopped with %v, want context.DeadlineExceeded", err)
+	}
+}
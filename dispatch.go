@@ -0,0 +1,185 @@
+package revoltgo
+
+import (
+	"context"
+	"runtime"
+	"sync"
+)
+
+// DispatchMode decides where user handlers run; see Session.DispatchMode.
+//
+// In every mode but DispatchParallel, frames are read and decoded one at a time, and the library's own handlers
+// keep the State up to date in the order events were received. Only the user handlers are then run as the mode
+// decides, so a slow handler never delays the State, though handlers may observe a State that has moved on.
+type DispatchMode int
+
+const (
+	// DispatchParallel processes frames in parallel on the Websocket's readers (runtime.NumCPU of them),
+	// library handlers included. Handlers run as soon as possible, in no particular order. The default
+	DispatchParallel DispatchMode = iota
+
+	// DispatchInline runs handlers on the reader, one event at a time, in the order received.
+	// A slow handler delays every event after it
+	DispatchInline
+
+	// DispatchAsync runs the handlers of every event in their own goroutine; no ordering and no limit
+	DispatchAsync
+
+	// DispatchWorkerPool runs handlers on Session.DispatchWorkers goroutines, in no particular order.
+	// When every worker is busy, reading from the Websocket waits for one to free up
+	DispatchWorkerPool
+
+	// DispatchOrdered runs the handlers of events about the same channel in the order received, or of the same
+	// server for events without a channel. Events about different channels or servers are handled concurrently,
+	// and events about neither (e.g. EventReady) are handled in order with each other
+	DispatchOrdered
+)
+
+// dispatcher runs jobs (the user handlers of an event) according to a DispatchMode.
+type dispatcher struct {
+	ctx  context.Context
+	mode DispatchMode
+
+	// DispatchWorkerPool. sending is held (shared) while a job is sent, and by stopping workers before they drain
+	jobs        chan func()
+	sending     sync.RWMutex
+	startWorker sync.Once
+	workers     int
+
+	// DispatchOrdered: the jobs waiting behind the one running, per key. A key is present while its goroutine runs
+	queuesMu sync.Mutex
+	queues   map[string][]func()
+
+	// running tracks the goroutines started for jobs, so that wait can tell when all of them are done
+	running sync.WaitGroup
+}
+
+func newDispatcher(ctx context.Context, mode DispatchMode, workers int) *dispatcher {
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+
+	return &dispatcher{
+		ctx:     ctx,
+		mode:    mode,
+		jobs:    make(chan func(), workers),
+		workers: workers,
+		queues:  make(map[string][]func()),
+	}
+}
+
+// run runs job as the mode decides. event is only used to find the key of DispatchOrdered.
+func (d *dispatcher) run(event any, job func()) {
+	switch d.mode {
+	case DispatchAsync:
+		d.running.Go(job)
+	case DispatchWorkerPool:
+		// The workers stop with the Websocket, but events such as EventDisconnected are dispatched after that
+		if d.ctx.Err() == nil {
+			d.startWorker.Do(d.startWorkers)
+		}
+
+		if !d.enqueue(job) {
+			job()
+		}
+	case DispatchOrdered:
+		d.ordered(dispatchKey(event), job)
+	default:
+		job()
+	}
+}
+
+func (d *dispatcher) startWorkers() {
+	for range d.workers {
+		d.running.Go(func() {
+			for {
+				select {
+				case job := <-d.jobs:
+					job()
+				case <-d.ctx.Done():
+					// Wait out the sends in progress; any later one sees ctx is done, so drain leaves nothing behind
+					d.sending.Lock()
+					d.sending.Unlock()
+
+					d.drain()
+					return
+				}
+			}
+		})
+	}
+}
+
+// enqueue hands job to the workers, and reports false if they are stopping, for the caller to run it.
+func (d *dispatcher) enqueue(job func()) bool {
+	d.sending.RLock()
+	defer d.sending.RUnlock()
+
+	// Checked first, since select picks at random between a done ctx and room in the queue
+	if d.ctx.Err() != nil {
+		return false
+	}
+
+	select {
+	case d.jobs <- job:
+		return true
+	case <-d.ctx.Done():
+		return false
+	}
+}
+
+// drain runs the jobs still queued for the workers, so none are lost when they stop.
+func (d *dispatcher) drain() {
+	for {
+		select {
+		case job := <-d.jobs:
+			job()
+		default:
+			return
+		}
+	}
+}
+
+// wait blocks until every job handed to a goroutine has run and, once ctx is done, the workers have stopped.
+func (d *dispatcher) wait() {
+	d.running.Wait()
+	d.drain()
+}
+
+// ordered runs job after the jobs already queued for key, starting a goroutine for the key if there is none.
+func (d *dispatcher) ordered(key string, job func()) {
+	d.queuesMu.Lock()
+	if queued, running := d.queues[key]; running {
+		d.queues[key] = append(queued, job)
+		d.queuesMu.Unlock()
+		return
+	}
+	d.queues[key] = nil
+	d.queuesMu.Unlock()
+
+	d.running.Go(func() {
+		for job != nil {
+			job()
+
+			d.queuesMu.Lock()
+			queued := d.queues[key]
+			if len(queued) == 0 {
+				delete(d.queues, key) // The next job for key starts a new goroutine
+				job = nil
+			} else {
+				job = queued[0]
+				d.queues[key] = queued[1:]
+			}
+			d.queuesMu.Unlock()
+		}
+	})
+}
+
+// dispatchKey is the channel an event is about, or else its server.
+func dispatchKey(event any) string {
+	server, channel, _, _ := eventIDs(event)
+	if channel != "" {
+		return channel
+	}
+
+	return server
+}
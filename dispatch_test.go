@@ -0,0 +1,128 @@
+package revoltgo_test
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/sentinelb51/revoltgo"
+	"github.com/sentinelb51/revoltgo/revolttest"
+)
+
+// timeout bounds every wait of the tests.
+const timeout = 5 * time.Second
+
+var dispatchModes = []struct {
+	name    string
+	mode    revoltgo.DispatchMode
+	ordered bool // Whether events about the same channel are handled in the order received
+}{
+	{"Parallel", revoltgo.DispatchParallel, false},
+	{"Inline", revoltgo.DispatchInline, true},
+	{"Async", revoltgo.DispatchAsync, false},
+	{"WorkerPool", revoltgo.DispatchWorkerPool, false},
+	{"Ordered", revoltgo.DispatchOrdered, true},
+}
+
+// TestDispatchModes pushes messages to a few channels; every mode must handle each of them once,
+// and the ordered ones must handle those of a channel in the order they were sent.
+func TestDispatchModes(t *testing.T) {
+	const (
+		channels = 4
+		events   = 200
+	)
+
+	for _, test := range dispatchModes {
+		t.Run(test.name, func(t *testing.T) {
+			server := revolttest.NewServer()
+			defer server.Close()
+
+			session := server.Session("token")
+			session.DispatchMode = test.mode
+			session.DispatchWorkers = 4
+
+			var (
+				mu       sync.Mutex
+				received = make(map[string][]int)
+				handled  int
+				done     = make(chan struct{})
+			)
+
+			revoltgo.AddHandler(session, func(_ *revoltgo.Session, e *revoltgo.EventMessage) {
+				n, _ := strconv.Atoi(e.Content)
+
+				mu.Lock()
+				defer mu.Unlock()
+
+				received[e.Channel] = append(received[e.Channel], n)
+				if handled++; handled == events {
+					close(done)
+				}
+			})
+
+			open(t, session)
+
+			for i := range events {
+				err := server.Push(&revoltgo.EventMessage{Message: revoltgo.Message{
+					ID:      strconv.Itoa(i),
+					Channel: "channel" + strconv.Itoa(i%channels),
+					Content: strconv.Itoa(i),
+				}})
+
+				if err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			wait(t, done, "every event to be handled")
+
+			mu.Lock()
+			defer mu.Unlock()
+
+			seen := make(map[int]bool, events)
+			for channel, numbers := range received {
+				for i, n := range numbers {
+					if seen[n] {
+						t.Fatalf("handled event %d twice", n)
+					}
+					seen[n] = true
+
+					if test.ordered && i > 0 && n < numbers[i-1] {
+						t.Fatalf("handled event %d of %s after event %d", n, channel, numbers[i-1])
+					}
+				}
+			}
+		})
+	}
+}
+
+// open opens session, and waits until it is ready.
+func open(t *testing.T, session *revoltgo.Session, config ...revoltgo.StateConfig) {
+	t.Helper()
+
+	if err := session.Open(config...); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Cleanup(func() { _ = session.Close() })
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	if err := session.WaitUntilReady(ctx); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// wait waits for done to be closed, failing the test after a while.
+func wait(t *testing.T, done <-chan struct{}, what string) {
+	t.Helper()
+
+	select {
+	case <-done:
+	case <-time.After(timeout):
+		t.Fatalf("timed out waiting for %s", what)
+	}
+}
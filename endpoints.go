@@ -1,8 +1,11 @@
 package revoltgo
 
 import (
-	"log"
+	"log/slog"
+	"net/url"
 	"strconv"
+	"sync"
+	"sync/atomic"
 )
 
 /*
@@ -23,50 +26,127 @@ import (
 	 - Follow the same hierarchical structure as the constants
 */
 
-/* These base URLs are used by the Session.Request method */
-var (
-	apiURL = "https://api.stoat.chat"
-	cdnURL = "https://cdn.stoatusercontent.com"
+// InstanceURLs are the base URLs of the services of a Revolt instance.
+// Each HTTPClient has its own; see HTTPClient.URLs.
+type InstanceURLs struct {
+	API     string   // The REST API, e.g. "https://api.stoat.chat"
+	CDN     string   // Autumn, which serves and stores files
+	January string   // January, which proxies and embeds external content; empty if unknown or disabled
+	Voice   []string // LiveKit, the voice servers calls are placed on; empty if unknown or disabled
+}
+
+// baseURLs are InstanceURLs with their API and CDN parsed, for resolving requests.
+// They are replaced as a whole, never modified, so they can be read without locking.
+type baseURLs struct {
+	InstanceURLs
+
+	parsedAPI *url.URL
+	parsedCDN *url.URL
+
+	// Whether the CDN was set by the user, who knows better than the instance; see HTTPClient.discover
+	cdnSet bool
+
+	// Whether plain HTTP and hosts without a TLD are accepted; see AllowInsecureBaseURLs
+	insecure bool
+}
 
-	parsedAPIBase = mustParseURL(apiURL)
-	parsedCDNBase = mustParseURL(cdnURL)
+// withAPI returns a copy of b with the API base URL set to u.
+func (b baseURLs) withAPI(u *url.URL) *baseURLs {
+	b.API = u.String()
+	b.parsedAPI = u
+	return &b
+}
+
+// withCDN returns a copy of b with the CDN base URL set to u.
+func (b baseURLs) withCDN(u *url.URL, set bool) *baseURLs {
+	b.CDN = u.String()
+	b.parsedCDN = u
+	b.cdnSet = set
+	return &b
+}
+
+// withInsecure returns a copy of b that accepts, or stops accepting, insecure base URLs.
+func (b baseURLs) withInsecure(allow bool) *baseURLs {
+	b.insecure = allow
+	return &b
+}
+
+/* The base URLs of HTTPClients that haven't set their own; see SetBaseURL */
+var (
+	defaultURLsMu sync.Mutex // Only serialises writers; readers Load()
+	defaultURLs   atomic.Pointer[baseURLs]
 )
 
+func init() {
+	const (
+		apiURL = "https://api.stoat.chat"
+		cdnURL = "https://cdn.stoatusercontent.com"
+	)
+
+	defaultURLs.Store(&baseURLs{
+		InstanceURLs: InstanceURLs{API: apiURL, CDN: cdnURL},
+		parsedAPI:    mustParseURL(apiURL),
+		parsedCDN:    mustParseURL(cdnURL),
+	})
+}
+
+// AllowInsecureBaseURLs lets SetBaseURL and SetCDNURL accept plain HTTP and hosts without a TLD, such as
+// "http://localhost:14702" for a self-hosted development instance or a test server. Call it before setting the
+// URLs; like them, it applies to sessions without base URLs of their own (see HTTPClient.AllowInsecureBaseURLs).
+// Tokens are sent in the clear over HTTP, so only enable it for instances you trust on networks you trust.
+func AllowInsecureBaseURLs(allow bool) {
+	defaultURLsMu.Lock()
+	defaultURLs.Store(defaultURLs.Load().withInsecure(allow))
+	defaultURLsMu.Unlock()
+}
+
+// BaseURL returns the default base URL of the API; see SetBaseURL.
 func BaseURL() string {
-	return apiURL
+	return defaultURLs.Load().API
 }
 
+// CDNURL returns the default base URL of the CDN; see SetCDNURL.
 func CDNURL() string {
-	return cdnURL
+	return defaultURLs.Load().CDN
 }
 
-// SetBaseURL sets the base URL for the API.
-// Call before opening any sessions; this is not mutex-protected.
+// SetBaseURL sets the default base URL for the API. It may have a path, e.g. "https://example.com/api" behind a
+// reverse proxy. It applies to every session without a base URL of its own (see HTTPClient.SetBaseURL) that
+// is opened afterwards.
 func SetBaseURL(newURL string) error {
-	u, err := validateBaseURL(newURL)
+	defaultURLsMu.Lock()
+	defer defaultURLsMu.Unlock()
+
+	urls := defaultURLs.Load()
+
+	u, err := validateBaseURL(newURL, urls.insecure)
 	if err != nil {
 		return err
 	}
 
-	apiURL = u.String()
-	parsedAPIBase = u
+	defaultURLs.Store(urls.withAPI(u))
 
-	log.Printf("Base URL set to %s", apiURL)
+	slog.Debug("Base URL set", "url", u.String())
 	return nil
 }
 
-// SetCDNURL sets the base URL for the CDN (Autumn).
-// Call before opening any sessions; this is not mutex-protected.
+// SetCDNURL sets the default base URL for the CDN (Autumn). It may have a path, e.g. "https://example.com/autumn".
+// Like SetBaseURL, it applies to sessions without a CDN of their own; it also stops sessions from using the
+// CDN advertised by their instance.
 func SetCDNURL(newURL string) error {
-	u, err := validateBaseURL(newURL)
+	defaultURLsMu.Lock()
+	defer defaultURLsMu.Unlock()
+
+	urls := defaultURLs.Load()
+
+	u, err := validateBaseURL(newURL, urls.insecure)
 	if err != nil {
 		return err
 	}
 
-	cdnURL = u.String()
-	parsedCDNBase = u
+	defaultURLs.Store(urls.withCDN(u, true))
 
-	log.Printf("CDN URL set to %s", cdnURL)
+	slog.Debug("CDN URL set", "url", u.String())
 	return nil
 }
 
@@ -369,12 +449,22 @@ func EndpointAuthMFA(action string) string {
 
 /* CDN endpoints */
 
+// EndpointAutumn is the URL of a tag (bucket) of the default CDN; see HTTPClient.EndpointAutumn for a session's.
 func EndpointAutumn(tag string) string {
-	return cdnURL + "/" + tag
+	return endpointAutumn(defaultURLs.Load().CDN, tag)
+}
+
+// EndpointAutumnFile is the URL of a file on the default CDN; see HTTPClient.EndpointAutumnFile for a session's.
+func EndpointAutumnFile(tag, id, size string) string {
+	return endpointAutumnFile(defaultURLs.Load().CDN, tag, id, size)
+}
+
+func endpointAutumn(cdn, tag string) string {
+	return cdn + "/" + tag
 }
 
-func EndpointAutumnFile(tag, id, size string) (url string) {
-	url = cdnURL + "/" + tag + "/" + id
+func endpointAutumnFile(cdn, tag, id, size string) (url string) {
+	url = cdn + "/" + tag + "/" + id
 	if size != "" {
 		url += "?max_side=" + size
 	}
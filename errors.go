@@ -0,0 +1,107 @@
+package revoltgo
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/goccy/go-json"
+)
+
+// Sentinel errors matched by APIError through errors.Is, for example:
+//
+//	if errors.Is(err, revoltgo.ErrMissingPermission) {
+//		// explain to the user what permission the bot lacks
+//	}
+var (
+	ErrNotFound          = errors.New("not found")
+	ErrUnauthorized      = errors.New("unauthorized")
+	ErrMissingPermission = errors.New("missing permission")
+	ErrTooManyRequests   = errors.New("too many requests")
+	ErrFailedValidation  = errors.New("failed validation")
+)
+
+// Error types the API sends in the "type" field of an error response; not exhaustive.
+// See: https://github.com/stoatchat/stoatchat/blob/main/crates/core/result/src/lib.rs
+const (
+	APIErrorTypeNotFound              = "NotFound"
+	APIErrorTypeNotAuthenticated      = "NotAuthenticated"
+	APIErrorTypeInvalidSession        = "InvalidSession"
+	APIErrorTypeMissingPermission     = "MissingPermission"
+	APIErrorTypeMissingUserPermission = "MissingUserPermission"
+	APIErrorTypeFailedValidation      = "FailedValidation"
+)
+
+// APIError is returned by HTTPClient when the API responds with a non-successful status code.
+// Use errors.As to inspect it, or errors.Is with one of the Err* sentinels to classify it.
+type APIError struct {
+	StatusCode int    // HTTP status code of the response
+	Type       string // Revolt's error "type" field, e.g. "MissingPermission"; empty if the body wasn't an error object
+	Permission string // The missing permission, for MissingPermission and MissingUserPermission
+	Method     string // HTTP method of the failed request
+	Route      string // Route template of the failed request, e.g. "/channels/%s/messages"; IDs and tokens are left out
+	Body       []byte // Raw (truncated) response body
+
+	// RetryAfter is how long the API asked to wait, for 429 responses
+	RetryAfter time.Duration
+}
+
+// apiErrorBody is the shape of an error response from the API
+type apiErrorBody struct {
+	Type       string `json:"type"`
+	Permission string `json:"permission"`
+	RetryAfter int64  `json:"retry_after"` // milliseconds
+}
+
+func newAPIError(statusCode int, method, route string, body []byte) *APIError {
+	e := &APIError{
+		StatusCode: statusCode,
+		Method:     method,
+		Route:      route,
+		Body:       body,
+	}
+
+	// Not every error has a body (e.g. proxies), so decoding failures are not errors themselves
+	var decoded apiErrorBody
+	if json.Unmarshal(body, &decoded) == nil {
+		e.Type = decoded.Type
+		e.Permission = decoded.Permission
+		e.RetryAfter = time.Duration(decoded.RetryAfter) * time.Millisecond
+	}
+
+	return e
+}
+
+func (e *APIError) Error() string {
+	var reason string
+	switch {
+	case e.Permission != "":
+		reason = fmt.Sprintf("%s (%s)", e.Type, e.Permission)
+	case e.Type != "":
+		reason = e.Type
+	default:
+		reason = string(e.Body)
+	}
+
+	return fmt.Sprintf("%s %s: bad status code %d: %s", e.Method, e.Route, e.StatusCode, reason)
+}
+
+// Is allows errors.Is to match APIError against the Err* sentinels.
+func (e *APIError) Is(target error) bool {
+	switch target {
+	case ErrNotFound:
+		return e.StatusCode == http.StatusNotFound || e.Type == APIErrorTypeNotFound
+	case ErrUnauthorized:
+		return e.StatusCode == http.StatusUnauthorized ||
+			e.Type == APIErrorTypeNotAuthenticated || e.Type == APIErrorTypeInvalidSession
+	case ErrMissingPermission:
+		return e.Type == APIErrorTypeMissingPermission || e.Type == APIErrorTypeMissingUserPermission
+	case ErrTooManyRequests:
+		return e.StatusCode == http.StatusTooManyRequests
+	case ErrFailedValidation:
+		return e.StatusCode == http.StatusUnprocessableEntity || e.Type == APIErrorTypeFailedValidation
+	}
+
+	return false
+}
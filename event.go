@@ -2,6 +2,7 @@ package revoltgo
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 
 	"github.com/tinylib/msgp/msgp"
@@ -47,12 +48,29 @@ func eventTypeFromMSGP(data []byte) ([]byte, error) {
 
 type Event struct {
 	Type string `msg:"type" json:"type,omitempty"`
+
+	ctx context.Context // Set when the event is dispatched; see Context
 }
 
 func (e *Event) String() string {
 	return e.Type
 }
 
+// Context returns the context the event was dispatched with. If Session.Tracer is set, it carries the span of
+// the dispatch, so REST calls made with it (using the ...Ctx methods) are traced as part of handling the event.
+func (e *Event) Context() context.Context {
+	if e.ctx == nil {
+		return context.Background()
+	}
+
+	return e.ctx
+}
+
+// setContext is promoted to every event, so the Websocket can set the context of a decoded event.
+func (e *Event) setContext(ctx context.Context) {
+	e.ctx = ctx
+}
+
 var eventConstructors = map[string]func() msgp.Unmarshaler{
 	"Error":         func() msgp.Unmarshaler { return new(EventError) },
 	"Bulk":          func() msgp.Unmarshaler { return new(EventBulk) },
@@ -110,3 +128,121 @@ var eventConstructors = map[string]func() msgp.Unmarshaler{
 
 	"ReportCreate": func() msgp.Unmarshaler { return new(EventReportCreate) },
 }
+
+// syntheticEvents are events created by the library rather than received from the Websocket.
+// Handlers can be registered for them like any other event, but they are never decoded.
+var syntheticEvents = map[string]bool{
+	"ConnectionStateChange": true,
+	"Disconnected":          true,
+	"Reconnecting":          true,
+	"Resumed":               true,
+
+	"ServerUpdateSnapshot":       true,
+	"ServerDeleteSnapshot":       true,
+	"ServerRoleUpdateSnapshot":   true,
+	"ServerRoleDeleteSnapshot":   true,
+	"ServerMemberUpdateSnapshot": true,
+	"ServerMemberLeaveSnapshot":  true,
+	"ChannelUpdateSnapshot":      true,
+	"ChannelDeleteSnapshot":      true,
+	"UserUpdateSnapshot":         true,
+	"EmojiDeleteSnapshot":        true,
+	"MessageUpdateSnapshot":      true,
+	"MessageDeleteSnapshot":      true,
+	"BulkMessageDeleteSnapshot":  true,
+}
+
+// eventIDs returns the IDs of what an event is about; each is empty if the event doesn't carry it.
+func eventIDs(event any) (server, channel, message, user string) {
+	switch e := event.(type) {
+	case *EventMessage:
+		return "", e.Channel, e.ID, e.Author
+	case *EventMessageUpdate:
+		return "", e.Channel, e.ID, ""
+	case *EventMessageAppend:
+		return "", e.Channel, e.ID, ""
+	case *EventMessageDelete:
+		return "", e.Channel, e.ID, ""
+	case *EventBulkMessageDelete:
+		return "", e.Channel, "", ""
+	case *EventMessageReact:
+		return "", e.ChannelID, e.ID, e.UserID
+	case *EventMessageUnreact:
+		return "", e.ChannelID, e.ID, e.UserID
+	case *EventMessageRemoveReaction:
+		return "", e.ChannelID, e.ID, ""
+	case *EventChannelStartTyping:
+		return "", e.ID, "", e.User
+	case *EventChannelStopTyping:
+		return "", e.ID, "", e.User
+	case *EventChannelAck:
+		return "", e.ID, e.MessageID, e.User
+	case *EventChannelCreate:
+		if e.Server != nil {
+			server = *e.Server
+		}
+		return server, e.Channel.ID, "", ""
+	case *EventChannelUpdate:
+		return "", e.ID, "", ""
+	case *EventChannelDelete:
+		return "", e.ID, "", ""
+	case *EventChannelGroupJoin:
+		return "", e.ID, "", e.User
+	case *EventChannelGroupLeave:
+		return "", e.ID, "", e.User
+	case *EventVoiceChannelJoin:
+		return "", e.ID, "", e.State.ID
+	case *EventVoiceChannelLeave:
+		return "", e.ID, "", e.User
+	case *EventUserVoiceStateUpdate:
+		return "", e.ChannelID, "", e.ID
+	case *EventServerCreate:
+		return e.ID, "", "", ""
+	case *EventServerUpdate:
+		return e.ID, "", "", ""
+	case *EventServerDelete:
+		return e.ID, "", "", ""
+	case *EventServerMemberJoin:
+		return e.ID, "", "", e.User
+	case *EventServerMemberLeave:
+		return e.ID, "", "", e.User
+	case *EventServerMemberUpdate:
+		return e.ID.Server, "", "", e.ID.User
+	case *EventServerRoleUpdate:
+		return e.ID, "", "", ""
+	case *EventServerRoleDelete:
+		return e.ID, "", "", ""
+	case *EventServerRoleRanksUpdate:
+		return e.ID, "", "", ""
+	case *EventUserUpdate:
+		return "", "", "", e.ID
+
+	// Snapshots are about the same thing as the event they carry
+	case *EventServerUpdateSnapshot:
+		return eventIDs(e.EventServerUpdate)
+	case *EventServerDeleteSnapshot:
+		return eventIDs(e.EventServerDelete)
+	case *EventServerRoleUpdateSnapshot:
+		return eventIDs(e.EventServerRoleUpdate)
+	case *EventServerRoleDeleteSnapshot:
+		return eventIDs(e.EventServerRoleDelete)
+	case *EventServerMemberUpdateSnapshot:
+		return eventIDs(e.EventServerMemberUpdate)
+	case *EventServerMemberLeaveSnapshot:
+		return eventIDs(e.EventServerMemberLeave)
+	case *EventChannelUpdateSnapshot:
+		return eventIDs(e.EventChannelUpdate)
+	case *EventChannelDeleteSnapshot:
+		return eventIDs(e.EventChannelDelete)
+	case *EventUserUpdateSnapshot:
+		return eventIDs(e.EventUserUpdate)
+	case *EventMessageUpdateSnapshot:
+		return eventIDs(e.EventMessageUpdate)
+	case *EventMessageDeleteSnapshot:
+		return eventIDs(e.EventMessageDelete)
+	case *EventBulkMessageDeleteSnapshot:
+		return eventIDs(e.EventBulkMessageDelete)
+	}
+
+	return "", "", "", ""
+}
@@ -169,11 +169,13 @@ type EventWebhookUpdate struct {
 	Remove []string       `msg:"remove" json:"remove,omitempty"` // todo: why is this "remove" and not "clear"?
 }
 
+// EventMessageUpdate is sent when a message is edited. Data will only contain fields that were modified.
 type EventMessageUpdate struct {
 	Event   `msg:",flatten"`
-	ID      string  `msg:"id" json:"id,omitempty"`
-	Channel string  `msg:"channel" json:"channel,omitempty"`
-	Data    Message `msg:"data" json:"data,omitempty"`
+	ID      string   `msg:"id" json:"id,omitempty"`
+	Channel string   `msg:"channel" json:"channel,omitempty"`
+	Data    Message  `msg:"data" json:"data,omitempty"`
+	Clear   []string `msg:"clear" json:"clear,omitempty"`
 }
 
 type EventMessageAppend struct {
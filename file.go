@@ -43,10 +43,17 @@ type File struct {
 	UserID   string `msg:"user_id" json:"user_id,omitempty"`
 }
 
+// URL is the URL of the file on the default CDN, scaled down to size if not empty.
+// Use Session.FileURL for sessions on another instance.
 func (a File) URL(size string) string {
 	return EndpointAutumnFile(a.Tag, a.ID, size)
 }
 
+// FileURL is like File.URL, but on the session's CDN, which may have been discovered from its instance.
+func (s *Session) FileURL(file File, size string) string {
+	return s.HTTP.EndpointAutumnFile(file.Tag, file.ID, size)
+}
+
 type AttachmentMetadata struct {
 	Type FileMetadataType `msg:"type" json:"type,omitempty"`
 
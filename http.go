@@ -2,15 +2,18 @@ package revoltgo
 
 import (
 	"bytes"
+	"context"
+	"errors"
 	"fmt"
 	"io"
-	"log"
 	"mime/multipart"
 	"net/http"
 	"net/url"
+	"slices"
 	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/goccy/go-json"
@@ -35,13 +38,18 @@ var zstdPool = sync.Pool{
 }
 
 type HTTPClient struct {
-	Debug bool
+	Debug bool // Logs requests, responses, retries and ratelimit waits at slog.LevelDebug
 
 	mu          sync.RWMutex
 	client      *http.Client
 	session     *Session
 	ratelimiter *Ratelimiter
 	headers     map[string]string
+	retry       RetryPolicy
+
+	// urls are this client's base URLs, or nil to use the defaults; urlsMu only serialises writers
+	urlsMu sync.Mutex
+	urls   atomic.Pointer[baseURLs]
 }
 
 func newHTTPClient(session *Session) *HTTPClient {
@@ -49,6 +57,7 @@ func newHTTPClient(session *Session) *HTTPClient {
 		session:     session,
 		client:      &http.Client{Timeout: 10 * time.Second},
 		ratelimiter: newRatelimiter(),
+		retry:       DefaultRetryPolicy(),
 		headers: map[string]string{
 			"User-Agent":      fmt.Sprintf("RevoltGo/%s (github.com/sentinelb51/revoltgo)", VERSION),
 			"Accept-Encoding": "zstd",
@@ -75,6 +84,139 @@ func (c *HTTPClient) SetTimeout(timeout time.Duration) error {
 	return nil
 }
 
+// SetClient replaces the underlying *http.Client, e.g. to use a proxy or trust a test server's certificate.
+// Call it before making requests; this is not mutex-protected. SetTimeout applies to the new client.
+func (c *HTTPClient) SetClient(client *http.Client) {
+	c.client = client
+}
+
+// URLs returns the base URLs this client sends requests to. Until set with SetBaseURL or SetCDNURL, or
+// discovered when the session is opened, they are the defaults (see the package-level SetBaseURL).
+func (c *HTTPClient) URLs() InstanceURLs {
+	urls := c.baseURLs().InstanceURLs
+	urls.Voice = slices.Clone(urls.Voice) // Shared with the client, which never modifies its URLs
+	return urls
+}
+
+// SetBaseURL sets the base URL of the API for this client only, e.g. to talk to a self-hosted instance and
+// the public one from the same process. Call it before opening the session.
+func (c *HTTPClient) SetBaseURL(newURL string) error {
+	c.urlsMu.Lock()
+	defer c.urlsMu.Unlock()
+
+	urls := c.baseURLs()
+
+	u, err := validateBaseURL(newURL, urls.insecure)
+	if err != nil {
+		return err
+	}
+
+	c.urls.Store(urls.withAPI(u))
+	return nil
+}
+
+// SetCDNURL sets the base URL of the CDN for this client only. Once set, the CDN advertised by the
+// instance is ignored.
+func (c *HTTPClient) SetCDNURL(newURL string) error {
+	c.urlsMu.Lock()
+	defer c.urlsMu.Unlock()
+
+	urls := c.baseURLs()
+
+	u, err := validateBaseURL(newURL, urls.insecure)
+	if err != nil {
+		return err
+	}
+
+	c.urls.Store(urls.withCDN(u, true))
+	return nil
+}
+
+// AllowInsecureBaseURLs is like the package-level AllowInsecureBaseURLs, but for this client only.
+// Call it before SetBaseURL and SetCDNURL.
+func (c *HTTPClient) AllowInsecureBaseURLs(allow bool) {
+	c.urlsMu.Lock()
+	c.urls.Store(c.baseURLs().withInsecure(allow))
+	c.urlsMu.Unlock()
+}
+
+// EndpointAutumn is like the package-level EndpointAutumn, but on this client's CDN.
+func (c *HTTPClient) EndpointAutumn(tag string) string {
+	return endpointAutumn(c.baseURLs().CDN, tag)
+}
+
+// EndpointAutumnFile is like the package-level EndpointAutumnFile, but on this client's CDN.
+func (c *HTTPClient) EndpointAutumnFile(tag, id, size string) string {
+	return endpointAutumnFile(c.baseURLs().CDN, tag, id, size)
+}
+
+// baseURLs returns the client's base URLs, falling back to the defaults.
+func (c *HTTPClient) baseURLs() *baseURLs {
+	if urls := c.urls.Load(); urls != nil {
+		return urls
+	}
+
+	return defaultURLs.Load()
+}
+
+// discover adopts the URLs of the services the instance advertises. The CDN is only replaced if it wasn't set
+// explicitly, and only if the advertised one passes the same checks as SetCDNURL.
+func (c *HTTPClient) discover(instance InstanceConfig) {
+	c.urlsMu.Lock()
+	defer c.urlsMu.Unlock()
+
+	next := c.baseURLs()
+
+	if autumn := instance.Features.Autumn; autumn.Enabled && autumn.URL != "" && !next.cdnSet {
+		if u, err := validateBaseURL(autumn.URL, next.insecure); err != nil {
+			c.session.logger().Warn("Ignoring the instance's CDN", "url", autumn.URL, "err", err)
+		} else {
+			next = next.withCDN(u, false)
+		}
+	}
+
+	urls := *next
+	urls.January, urls.Voice = "", nil
+
+	if january := instance.Features.January; january.Enabled {
+		urls.January = strings.TrimRight(january.URL, "/")
+	}
+
+	// Voice is gated on the same feature; see InstanceConfigFeatures.Voice
+	if instance.Features.Voice() {
+		for _, node := range instance.Features.LiveKit.Nodes {
+			urls.Voice = append(urls.Voice, strings.TrimRight(node.PublicURL, "/"))
+		}
+	}
+
+	c.urls.Store(&urls)
+}
+
+// SetRetryPolicy replaces the policy used to retry ratelimited and transiently failed requests.
+// Use RetryPolicy{} to disable retries entirely.
+func (c *HTTPClient) SetRetryPolicy(policy RetryPolicy) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.retry = policy
+}
+
+// RetryPolicy returns the current retry policy
+func (c *HTTPClient) RetryPolicy() RetryPolicy {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.retry
+}
+
+// SetRatelimitStore replaces where ratelimit buckets are kept; see Ratelimiter.SetStore
+func (c *HTTPClient) SetRatelimitStore(store RatelimitStore) {
+	c.ratelimiter.SetStore(store)
+}
+
+// Ratelimiter returns the client's ratelimiter, e.g. to set a global limit or observe queue depth
+func (c *HTTPClient) Ratelimiter() *Ratelimiter {
+	return c.ratelimiter
+}
+
 // AddHeader adds a header and checks if it already exists
 func (c *HTTPClient) AddHeader(key, value string) error {
 	c.mu.Lock()
@@ -110,12 +252,18 @@ func (c *HTTPClient) Header(key string) string {
 }
 
 // ResolveURL converts a relative URL to an absolute URL. Prefixes relative URLs with the API base URL.
-// It also allows absolute URLs targeting the CDN. Otherwise, it rejects the URL.
+// It also allows absolute URLs targeting the CDN. Otherwise, or if its path has a ".." segment, it rejects the URL.
 func (c *HTTPClient) ResolveURL(destination string) (string, error) {
+	return resolveURL(c.baseURLs(), destination)
+}
+
+func resolveURL(urls *baseURLs, destination string) (string, error) {
 
 	// Fast path: our endpoints are usually absolute paths ("/endpoint") -> Skip url.Parse/ResolveReference.
-	if strings.HasPrefix(destination, "/") && !strings.HasPrefix(destination, "//") {
-		return apiURL + destination, nil
+	// Anything that may hide a ".." segment, even percent-encoded, is parsed to be checked.
+	if strings.HasPrefix(destination, "/") && !strings.HasPrefix(destination, "//") &&
+		!strings.Contains(destination, "..") && !strings.Contains(destination, "%") {
+		return urls.API + destination, nil
 	}
 
 	destination = strings.TrimSpace(destination)
@@ -128,21 +276,45 @@ func (c *HTTPClient) ResolveURL(destination string) (string, error) {
 		return "", fmt.Errorf("parse(destination): %w", err)
 	}
 
-	// Reject scheme-less URLs (//host/path) and any provided scheme.
+	// ".." would step out of the base path, whether JoinPath cleans it or the server does
+	if hasDotDot(u.Path) {
+		return "", fmt.Errorf("refusing path with \"..\" segments")
+	}
+
+	// Reject scheme-less URLs (//host/path), and absolute URLs outside the API and the CDN.
 	if u.Scheme != "" || u.Host != "" {
-		if sameHostname(u, parsedAPIBase) || sameHostname(u, parsedCDNBase) {
+		if withinBase(u, urls.parsedAPI) || withinBase(u, urls.parsedCDN) {
 			return u.String(), nil
 		}
-		return "", fmt.Errorf("refusing external URL host %q", u.Host)
+		return "", fmt.Errorf("refusing external URL %q", u.Redacted())
 	}
 
-	// Path-only (or query/fragment) reference.
-	return parsedAPIBase.ResolveReference(u).String(), nil
+	// Path-only (or query/fragment) reference. Relative to the base's path, which ResolveReference would replace
+	resolved := urls.parsedAPI.JoinPath(u.Path)
+	resolved.RawQuery = u.RawQuery
+	resolved.Fragment = u.Fragment
+	return resolved.String(), nil
 }
 
-func sameHostname(a, b *url.URL) bool {
+// hasDotDot reports whether a path has a ".." segment.
+func hasDotDot(path string) bool {
+	for segment := range strings.SplitSeq(path, "/") {
+		if segment == ".." {
+			return true
+		}
+	}
+
+	return false
+}
+
+// withinBase reports whether u is on the same scheme and host as base, and under its path.
+func withinBase(u, base *url.URL) bool {
 	// Host may include port; compare case-insensitively.
-	return strings.EqualFold(a.Host, b.Host)
+	if !strings.EqualFold(u.Scheme, base.Scheme) || !strings.EqualFold(u.Host, base.Host) {
+		return false
+	}
+
+	return base.Path == "" || u.Path == base.Path || strings.HasPrefix(u.Path, base.Path+"/")
 }
 
 // printDebugTX logs the outgoing request details if debugging is enabled.
@@ -157,14 +329,14 @@ func (c *HTTPClient) printDebugTX(method, destination string, data any) {
 			}
 		}
 	}
-	log.Printf("[HTTP/TX] %s %s -> %s", method, destination, payload)
+	c.session.logger().Debug("[HTTP/TX]", "method", method, "endpoint", destination, "payload", payload)
 }
 
 // printDebugRX logs the incoming response body and returns a replayable reader
 // so handleResponse can read it again.
 func (c *HTTPClient) printDebugRX(statusCode int, body io.Reader) io.Reader {
 	bodyBytes, _ := io.ReadAll(body)
-	log.Printf("[HTTP/RX] %d %s", statusCode, string(bodyBytes))
+	c.session.logger().Debug("[HTTP/RX]", "status", statusCode, "body", string(bodyBytes))
 	return bytes.NewReader(bodyBytes)
 }
 
@@ -177,30 +349,114 @@ Request sends a JSON Request with "method" to a destination URL
 This function automatically handles rate-limiting and response status codes
 */
 func (c *HTTPClient) Request(method, destination string, data, result any) error {
+	return c.RequestContext(context.Background(), method, destination, data, result)
+}
+
+// RequestContext is like Request, but bound to ctx. Cancelling ctx aborts the
+// ratelimit wait, the body upload, and reading (and decompressing) the response.
+func (c *HTTPClient) RequestContext(ctx context.Context, method, destination string, data, result any) (err error) {
 
-	destination, err := c.ResolveURL(destination)
+	urls := c.baseURLs()
+
+	destination, err = resolveURL(urls, destination)
 	if err != nil {
 		return err
 	}
 
-	rl := c.ratelimiter.get(method, destination)
+	route := newRatelimitRoute(method, destination, urls.API)
+	policy := c.RetryPolicy()
+
+	ctx, span := c.session.tracer().Start(ctx, "revoltgo.request "+method+" "+route.path(),
+		Attribute{Key: AttributeHTTPMethod, Value: method},
+		Attribute{Key: AttributeHTTPRoute, Value: route.path()},
+	)
+	defer func() { endSpan(span, err) }()
+
+	// Uploads can only be repeated if the file can be rewound
+	file, isUpload := data.(*FileParams)
+	seeker, canRewind := io.Seeker(nil), true
+	if isUpload {
+		seeker, canRewind = file.Reader.(io.Seeker)
+	}
+
+	for attempt := 1; ; attempt++ {
+		statusCode, wait, err := c.attempt(ctx, route, method, destination, data, result)
+		span.SetAttributes(
+			Attribute{Key: AttributeHTTPStatusCode, Value: statusCode},
+			Attribute{Key: AttributeHTTPAttempt, Value: attempt - 1},
+		)
+
+		if err == nil || ctx.Err() != nil || !canRewind || !policy.shouldRetry(attempt, method, statusCode) {
+			return err
+		}
+
+		if isUpload {
+			if _, seekErr := seeker.Seek(0, io.SeekStart); seekErr != nil {
+				return fmt.Errorf("rewind upload: %w", seekErr)
+			}
+		}
+
+		// A ratelimited retry waits in the bucket, so concurrent requests to it also back off
+		if statusCode == http.StatusTooManyRequests {
+			if blockErr := c.ratelimiter.block(route, wait); blockErr != nil {
+				return blockErr
+			}
+			wait = 0
+		} else {
+			wait = policy.delay(attempt)
+		}
 
-	if wait := rl.delay(); wait > 0 {
 		if c.Debug {
-			log.Printf("[HTTP/RATELIMIT] %s %s, waiting %s", method, destination, wait)
+			c.session.logger().Debug("[HTTP/RETRY]", "method", method, "route", route.path(), "attempt", attempt, "err", err, "wait", wait)
 		}
 
-		time.Sleep(wait)
+		if err = sleepContext(ctx, wait); err != nil {
+			return err
+		}
 	}
+}
+
+// attempt performs a single round trip. It returns the response status code (0 if no response was received),
+// and for ratelimited responses, how long the server asked us to wait.
+func (c *HTTPClient) attempt(ctx context.Context, route ratelimitRoute, method, destination string, data, result any) (int, time.Duration, error) {
+
+	metrics := c.session.metrics()
+	tracer := c.session.tracer()
+	path := route.path()
 
-	reader, contentType, err := c.prepareRequestBody(data)
+	_, span := tracer.Start(ctx, "revoltgo.ratelimit")
+	queued := time.Now()
+	bucket, err := c.ratelimiter.wait(ctx, route)
+	endSpan(span, err)
 	if err != nil {
-		return err
+		return 0, 0, err
+	}
+
+	// A request that doesn't report the limit must not hold back the others waiting to learn it
+	reported := false
+	defer func() {
+		if !reported {
+			c.ratelimiter.release(bucket)
+		}
+	}()
+
+	waited := time.Since(queued)
+	metrics.ObserveRatelimitWait(method, path, waited)
+
+	if c.Debug && waited > time.Millisecond {
+		c.session.logger().Debug("[HTTP/RATELIMIT]", "method", method, "route", path, "waited", waited)
 	}
 
-	request, err := http.NewRequest(method, destination, reader)
+	sent := time.Now()
+
+	reader, contentType, err := c.prepareRequestBody(ctx, data)
 	if err != nil {
-		return err
+		return 0, 0, err
+	}
+
+	request, err := http.NewRequestWithContext(ctx, method, destination, reader)
+	if err != nil {
+		return 0, 0, err
 	}
 
 	request.Header.Set("Content-Type", contentType)
@@ -221,16 +477,32 @@ func (c *HTTPClient) Request(method, destination string, data, result any) error
 		c.printDebugTX(method, destination, data)
 	}
 
+	_, span = tracer.Start(ctx, "revoltgo.send")
 	response, err := c.client.Do(request)
+	endSpan(span, err)
 	if err != nil {
-		return err
+		metrics.ObserveRequest(method, path, 0, time.Since(sent))
+		return 0, 0, err
 	}
 	defer response.Body.Close()
 
-	if err = rl.update(response.Header); err != nil {
-		return err
+	// Deferred so the duration covers reading and decoding the body
+	defer func() {
+		metrics.ObserveRequest(method, path, response.StatusCode, time.Since(sent))
+	}()
+
+	// The response may reveal which group this route belongs to; its headers describe that group's bucket
+	bucket = c.ratelimiter.learn(route, response.Header)
+	if reported, err = c.ratelimiter.update(bucket, response.Header); err != nil {
+		return response.StatusCode, 0, err
+	}
+
+	var wait time.Duration
+	if response.StatusCode == http.StatusTooManyRequests {
+		wait = retryAfter(response.Header)
 	}
 
+	_, span = tracer.Start(ctx, "revoltgo.decode")
 	body := io.Reader(response.Body)
 
 	// Handle ZStandard decompression
@@ -248,24 +520,33 @@ func (c *HTTPClient) Request(method, destination string, data, result any) error
 		body = c.printDebugRX(response.StatusCode, body)
 	}
 
-	return c.handleResponse(response.StatusCode, body, result)
+	err = c.handleResponse(method, path, response.StatusCode, body, result)
+	endSpan(span, err)
+
+	// Fall back to the body's hint if the ratelimit headers were missing
+	if apiErr, ok := errors.AsType[*APIError](err); ok && wait == 0 {
+		wait = apiErr.RetryAfter
+	}
+
+	return response.StatusCode, wait, err
 }
 
 // prepareRequestBody prepares an appropriate Request body and determines the content type
-func (c *HTTPClient) prepareRequestBody(body any) (io.Reader, string, error) {
+func (c *HTTPClient) prepareRequestBody(ctx context.Context, body any) (io.Reader, string, error) {
 	if body == nil {
 		return http.NoBody, "application/json", nil
 	}
 
 	if file, ok := body.(*FileParams); ok {
-		return c.prepareFileUpload(file)
+		return c.prepareFileUpload(ctx, file)
 	}
 
 	return c.prepareJSONBody(body)
 }
 
-// prepareFileUpload prepares a multipart form for uploading a file
-func (c *HTTPClient) prepareFileUpload(file *FileParams) (io.Reader, string, error) {
+// prepareFileUpload prepares a multipart form for uploading a file.
+// The copy stops early once ctx is cancelled, so an abandoned upload doesn't keep reading the file.
+func (c *HTTPClient) prepareFileUpload(ctx context.Context, file *FileParams) (io.Reader, string, error) {
 	reader, writer := io.Pipe()
 	form := multipart.NewWriter(writer)
 
@@ -277,7 +558,7 @@ func (c *HTTPClient) prepareFileUpload(file *FileParams) (io.Reader, string, err
 			return
 		}
 
-		if _, err = io.Copy(part, file.Reader); err != nil {
+		if _, err = io.Copy(part, contextReader{ctx: ctx, reader: file.Reader}); err != nil {
 			writer.CloseWithError(fmt.Errorf("io.Copy: %w", err))
 			return
 		}
@@ -298,8 +579,8 @@ func (c *HTTPClient) prepareJSONBody(body any) (io.Reader, string, error) {
 	return bytes.NewReader(data), "application/json", nil
 }
 
-// handleResponse processes the API response
-func (c *HTTPClient) handleResponse(statusCode int, body io.Reader, result any) error {
+// handleResponse processes the API response. Unsuccessful status codes are returned as *APIError
+func (c *HTTPClient) handleResponse(method, route string, statusCode int, body io.Reader, result any) error {
 	switch statusCode {
 	case http.StatusNoContent:
 		return nil
@@ -321,7 +602,7 @@ func (c *HTTPClient) handleResponse(statusCode int, body io.Reader, result any)
 	default:
 		const limit = 1024
 		message, _ := io.ReadAll(io.LimitReader(body, limit))
-		return fmt.Errorf("bad status code %d: %s", statusCode, message)
+		return newAPIError(statusCode, method, route, message)
 	}
 
 	return nil
@@ -0,0 +1,80 @@
+package revoltgo_test
+
+import (
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/sentinelb51/revoltgo"
+	"github.com/sentinelb51/revoltgo/revolttest"
+)
+
+func TestVoiceDisabled(t *testing.T) {
+	server := revolttest.NewServer()
+	defer server.Close()
+
+	session := server.Session("token")
+	if _, err := session.FetchInstance(); err != nil {
+		t.Fatal(err)
+	}
+
+	if voice := session.HTTP.URLs().Voice; len(voice) > 0 {
+		t.Fatalf("voice URLs are %v without LiveKit", voice)
+	}
+
+	_, err := session.ChannelsJoinCall("general", revoltgo.ChannelJoinCallParams{})
+	if !errors.Is(err, revoltgo.ErrFeatureDisabled) {
+		t.Fatalf("joining a call without LiveKit: %v, want ErrFeatureDisabled", err)
+	}
+
+	if len(server.Requests()) != 1 {
+		t.Fatalf("sent %d requests, want only the instance's", len(server.Requests()))
+	}
+}
+
+func TestVoiceEnabled(t *testing.T) {
+	server := revolttest.NewServer()
+	defer server.Close()
+
+	server.Instance.Features.LiveKit = revoltgo.InstanceConfigFeaturesLiveKit{
+		Enabled: true,
+		Nodes:   []revoltgo.InstanceConfigFeaturesLiveKitNode{{Name: "eu", PublicURL: "wss://livekit.example.com/"}},
+	}
+
+	session := server.Session("token")
+	if _, err := session.FetchInstance(); err != nil {
+		t.Fatal(err)
+	}
+
+	if voice := session.HTTP.URLs().Voice; !slices.Equal(voice, []string{"wss://livekit.example.com"}) {
+		t.Fatalf("voice URLs are %v", voice)
+	}
+
+	// The fake server doesn't place calls, but the request must be sent
+	_, err := session.ChannelsJoinCall("general", revoltgo.ChannelJoinCallParams{})
+	if !errors.Is(err, revoltgo.ErrNotFound) {
+		t.Fatalf("joining a call with LiveKit: %v, want ErrNotFound", err)
+	}
+}
+
+func TestAllowInsecureBaseURLs(t *testing.T) {
+	const local = "http://localhost:14702"
+
+	session := revoltgo.New("token")
+	if err := session.HTTP.SetBaseURL(local); err == nil {
+		t.Fatal("accepted an insecure base URL without AllowInsecureBaseURLs")
+	}
+
+	session.HTTP.AllowInsecureBaseURLs(true)
+	if err := session.HTTP.SetBaseURL(local); err != nil {
+		t.Fatal(err)
+	}
+
+	if api := session.HTTP.URLs().API; api != local {
+		t.Fatalf("base URL is %s, want %s", api, local)
+	}
+
+	if err := revoltgo.SetBaseURL(local); err == nil {
+		t.Fatal("allowing insecure base URLs on one client allowed them for the defaults")
+	}
+}
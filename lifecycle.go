@@ -0,0 +1,71 @@
+package revoltgo
+
+import (
+	"errors"
+	"time"
+)
+
+// ConnectionState is the state of the Websocket connection; see Websocket.ConnectionState.
+type ConnectionState int32
+
+const (
+	ConnectionStateClosed         ConnectionState = iota // Not connected, and not trying to; the zero value
+	ConnectionStateConnecting                            // Dialing the server
+	ConnectionStateAuthenticating                        // Connected, waiting for the server to authenticate us
+	ConnectionStateReady                                 // Authenticated, and the State is populated (or was resumed)
+	ConnectionStateReconnecting                          // Lost the connection, waiting to reconnect
+)
+
+func (cs ConnectionState) String() string {
+	switch cs {
+	case ConnectionStateClosed:
+		return "Closed"
+	case ConnectionStateConnecting:
+		return "Connecting"
+	case ConnectionStateAuthenticating:
+		return "Authenticating"
+	case ConnectionStateReady:
+		return "Ready"
+	case ConnectionStateReconnecting:
+		return "Reconnecting"
+	}
+
+	return "Unknown"
+}
+
+// ErrConnectionClosed is returned by Session.WaitUntilReady when the connection is closed and won't become ready.
+var ErrConnectionClosed = errors.New("connection closed")
+
+// Lifecycle events are dispatched by the library as the Websocket connection changes. They are registered like
+// any other event:
+//
+//	revoltgo.AddHandler(session, func(s *revoltgo.Session, e *revoltgo.EventResumed) {
+//		slog.Warn("Resumed; events may have been missed", "downtime", e.Downtime)
+//	})
+
+// EventConnectionStateChange is dispatched on every transition of Websocket.ConnectionState.
+type EventConnectionStateChange struct {
+	Event
+	From ConnectionState
+	To   ConnectionState
+}
+
+// EventDisconnected is dispatched when the Websocket connection is lost or closed.
+// Events sent from now on are missed, until EventResumed or a new EventReady.
+type EventDisconnected struct {
+	Event
+	Err error // Why the connection was lost; nil if it was closed gracefully
+}
+
+// EventReconnecting is dispatched before every attempt to reconnect.
+type EventReconnecting struct {
+	Event
+	Attempt int // Starts at 1 after every disconnection
+}
+
+// EventResumed is dispatched when a reconnection kept the State (see Websocket.Resume), rather than rebuilding it
+// from a new EventReady. Anything that happened during Downtime was missed, so re-fetch whatever must be exact.
+type EventResumed struct {
+	Event
+	Downtime time.Duration
+}
@@ -1,6 +1,10 @@
 package revoltgo
 
-import "time"
+import (
+	"maps"
+	"slices"
+	"time"
+)
 
 //go:generate msgp -tests=false -io=false
 
@@ -79,6 +83,95 @@ type Message struct {
 	Webhook      *MessageWebhook `msg:"webhook" json:"webhook,omitempty"`
 }
 
+// update applies a MessageUpdate. Data will only contain fields that were modified.
+func (m *Message) update(data Message) {
+	if data.Content != "" {
+		m.Content = data.Content
+	}
+
+	if data.Edited != nil {
+		m.Edited = data.Edited
+	}
+
+	if data.Embeds != nil {
+		m.Embeds = data.Embeds
+	}
+
+	if data.Pinned {
+		m.Pinned = true
+	}
+
+	if data.Reactions != nil {
+		m.Reactions = data.Reactions
+	}
+
+	if data.Interactions != nil {
+		m.Interactions = data.Interactions
+	}
+}
+
+func (m *Message) clear(fields []string) (unknown []string) {
+	for _, field := range fields {
+		switch field {
+		case "Pinned":
+			m.Pinned = false
+		default:
+			unknown = append(unknown, field)
+		}
+	}
+
+	return unknown
+}
+
+// append applies a MessageAppend; the server only ever appends embeds (e.g. link previews).
+// Like react and unreact, it replaces the slice rather than editing it, since snapshots handed to handlers share it.
+func (m *Message) append(data Message) {
+	m.Embeds = append(slices.Clip(m.Embeds), data.Embeds...)
+}
+
+// react adds a user's reaction with an emoji.
+func (m *Message) react(uID, eID string) {
+	if slices.Contains(m.Reactions[eID], uID) {
+		return
+	}
+
+	reactions := maps.Clone(m.Reactions)
+	if reactions == nil {
+		reactions = make(map[string][]string)
+	}
+
+	reactions[eID] = append(slices.Clip(reactions[eID]), uID)
+	m.Reactions = reactions
+}
+
+// unreact removes a user's reaction with an emoji.
+func (m *Message) unreact(uID, eID string) {
+	index := slices.Index(m.Reactions[eID], uID)
+	if index < 0 {
+		return
+	}
+
+	reactions := maps.Clone(m.Reactions)
+	if users := slices.Delete(slices.Clone(reactions[eID]), index, index+1); len(users) > 0 {
+		reactions[eID] = users
+	} else {
+		delete(reactions, eID)
+	}
+
+	m.Reactions = reactions
+}
+
+// clearReaction removes every reaction with an emoji.
+func (m *Message) clearReaction(eID string) {
+	if _, exists := m.Reactions[eID]; !exists {
+		return
+	}
+
+	reactions := maps.Clone(m.Reactions)
+	delete(reactions, eID)
+	m.Reactions = reactions
+}
+
 // MessageWebhook is derived from:
 // https://github.com/stoatchat/stoatchat/blob/main/crates/core/models/src/v0/channel_webhooks.rs#L36
 type MessageWebhook struct {
@@ -0,0 +1,45 @@
+package revoltgo
+
+import "time"
+
+// Metrics receives measurements from the library's hot paths, to be exported to a monitoring system.
+// Set it on Session.Metrics before opening the session; see the revoltmetrics package for an expvar exporter.
+//
+// Methods are called synchronously from the HTTP client and the Websocket read loop, often concurrently,
+// so implementations must be safe for concurrent use and should return quickly.
+// Embed NoopMetrics to only implement the measurements you care about.
+type Metrics interface {
+	// ObserveRequest is called after every HTTP attempt, including retries.
+	// route is the path template, e.g. "/channels/%s/messages", or the URL outside the API (e.g. the CDN).
+	// status is 0 if no response was received
+	ObserveRequest(method, route string, status int, duration time.Duration)
+
+	// ObserveRatelimitWait is called with how long a request waited for its ratelimit bucket; usually zero
+	ObserveRatelimitWait(method, route string, wait time.Duration)
+
+	// ObserveHeartbeat is called with the Websocket latency whenever a heartbeat is acknowledged
+	ObserveHeartbeat(latency time.Duration)
+
+	// IncEventReceived is called for every event received from the Websocket
+	IncEventReceived(eventType string)
+
+	// IncEventDropped is called for received events without any handler, or of a type unknown to the library
+	IncEventDropped(eventType string)
+
+	// IncDecodeFailure is called when an event could not be decoded. eventType is empty if it couldn't be detected
+	IncDecodeFailure(eventType string)
+
+	// IncReconnect is called before every attempt to reconnect the Websocket
+	IncReconnect()
+}
+
+// NoopMetrics discards all measurements; it is used when Session.Metrics is nil.
+type NoopMetrics struct{}
+
+func (NoopMetrics) ObserveRequest(string, string, int, time.Duration)  {}
+func (NoopMetrics) ObserveRatelimitWait(string, string, time.Duration) {}
+func (NoopMetrics) ObserveHeartbeat(time.Duration)                     {}
+func (NoopMetrics) IncEventReceived(string)                            {}
+func (NoopMetrics) IncEventDropped(string)                             {}
+func (NoopMetrics) IncDecodeFailure(string)                            {}
+func (NoopMetrics) IncReconnect()                                      {}
@@ -0,0 +1,82 @@
+package revoltgo
+
+import (
+	"fmt"
+	"runtime/debug"
+	"slices"
+)
+
+// HandlerFunc is a user handler as seen by middleware: event is the pointer to the event struct, e.g. *EventMessage.
+type HandlerFunc func(s *Session, event any)
+
+// Middleware wraps user handlers, for concerns shared by all of them: recovery, timing, filtering, and so on.
+// It returns a HandlerFunc that usually calls next; not calling it skips the handler.
+//
+//	session.Use(func(next revoltgo.HandlerFunc) revoltgo.HandlerFunc {
+//		return func(s *revoltgo.Session, event any) {
+//			start := time.Now()
+//			next(s, event)
+//			slog.Info("Handled", "event", fmt.Sprintf("%T", event), "took", time.Since(start))
+//		}
+//	})
+type Middleware func(next HandlerFunc) HandlerFunc
+
+// Use wraps every user handler in middleware, including handlers that are already registered.
+// Middleware is called once per handler per event, in the order it was added; the first is the outermost.
+// The library's own handlers, which keep the State up to date, are never wrapped.
+func (s *Session) Use(middleware ...Middleware) {
+	s.handlersMu.Lock()
+	defer s.handlersMu.Unlock()
+
+	next := s.handlers.Load().clone()
+	next.middleware = slices.Concat(next.middleware, middleware)
+
+	// Replace rather than edit the registered handlers; a live dispatch may still read them
+	for name, handlers := range next.user {
+		wrapped := make([]*userHandler, len(handlers))
+		for i, h := range handlers {
+			wrapped[i] = &userHandler{id: h.id, handler: h.handler, call: chain(next.middleware, h.handler)}
+		}
+		next.user[name] = wrapped
+	}
+
+	s.handlers.Store(next)
+}
+
+// chain wraps handler in middleware, so that the first middleware is called first.
+func chain(middleware []Middleware, handler HandlerFunc) HandlerFunc {
+	for i := len(middleware) - 1; i >= 0; i-- {
+		handler = middleware[i](handler)
+	}
+
+	return handler
+}
+
+// Recover returns a Middleware that recovers from panicking handlers, so that the process and the
+// event's other handlers carry on. report is called with the panic value and the stack trace of the panic;
+// if it is nil, the panic is logged to the Session's Logger instead.
+//
+//	session.Use(revoltgo.Recover(nil))
+func Recover(report func(s *Session, event any, recovered any, stack []byte)) Middleware {
+	return func(next HandlerFunc) HandlerFunc {
+		return func(s *Session, event any) {
+			defer func() {
+				recovered := recover()
+				if recovered == nil {
+					return
+				}
+
+				stack := debug.Stack()
+				if report != nil {
+					report(s, event, recovered, stack)
+					return
+				}
+
+				s.logger().Error("Recovered from a panicking handler",
+					"event", fmt.Sprintf("%T", event), "panic", recovered, "stack", string(stack))
+			}()
+
+			next(s, event)
+		}
+	}
+}
@@ -1,7 +1,9 @@
 package revoltgo
 
 import (
+	"context"
 	"fmt"
+	"io"
 	"net/http"
 	"strconv"
 	"strings"
@@ -10,164 +12,435 @@ import (
 )
 
 /*
-	Ratelimiter uses a RWMutex since buckets are created once per endpoint, then read on every request (low write, high read)
-	Ratelimit buckets use a simple mutex as they are updated on every response
+	Ratelimiter uses a RWMutex since routes are learned once per route, then read on every request (low write, high read)
+	Ratelimit buckets (see ratelimitstore.go) use a simple mutex as they are updated on every response
 */
 
 const (
+	ratelimitHeaderBucket     = "X-RateLimit-Bucket"
+	ratelimitHeaderLimit      = "X-RateLimit-Limit"
 	ratelimitHeaderRemaining  = "X-RateLimit-Remaining"
 	ratelimitHeaderResetAfter = "X-RateLimit-Reset-After"
 )
 
-type ratelimitBucket struct {
-	sync.Mutex
-	remaining  int
-	resetAfter time.Time
+// ratelimitUnknownResetTimeout bounds how long to wait for headers from an in-flight request when a bucket
+// is used up and its reset time is unknown.
+const ratelimitUnknownResetTimeout = 5 * time.Second
+
+// RatelimitState is what a store knows about one ratelimit bucket.
+type RatelimitState struct {
+	Limit     int       // Requests allowed per window, or 0 if not known yet
+	Remaining int       // Requests left in the current window
+	Reset     time.Time // When the current window ends; zero if unknown
 }
 
-// Ratelimiter manages ratelimit buckets for different API endpoints.
-type Ratelimiter struct {
-	mu        sync.RWMutex
-	endpoints map[string]*ratelimitBucket
+// take consumes a request if the state allows one, refilling it first if its window has passed.
+func (s *RatelimitState) take(now time.Time) bool {
+	if s.Limit > 0 && !s.Reset.IsZero() && now.After(s.Reset) {
+		s.Remaining = s.Limit
+		s.Reset = time.Time{}
+	}
+
+	if s.Remaining > 0 {
+		s.Remaining--
+		return true
+	}
 
-	// Interval to clean-up stale ratelimit buckets.
-	CleanInterval time.Duration
-	// stop the background cleaner
-	stop     chan struct{}
-	stopOnce sync.Once
+	// Out of requests, and the limit is unknown: once the window has passed, let one request through to learn it,
+	// and hold the others until its response is recorded, it is released without one (see Ratelimiter.release),
+	// or ratelimitUnknownResetTimeout passes
+	if s.Limit == 0 && now.After(s.Reset) {
+		s.Reset = now.Add(ratelimitUnknownResetTimeout)
+		return true
+	}
+
+	return false
+}
+
+// RatelimitStore holds ratelimit buckets, keyed by an opaque bucket key. The default keeps them in memory;
+// a store shared between processes lets several replicas of the same token spend one budget.
+type RatelimitStore interface {
+	// Wait blocks until a request may be sent on the bucket, consuming it.
+	Wait(ctx context.Context, bucket string) error
+
+	// Update records the state a response reported for the bucket.
+	Update(bucket string, state RatelimitState) error
+
+	// Get returns the last known state of the bucket, and whether it is known at all.
+	Get(bucket string) (RatelimitState, bool)
+}
+
+// Ratelimiter maps API routes to ratelimit buckets, and waits on them through a RatelimitStore.
+type Ratelimiter struct {
+	mu     sync.RWMutex
+	store  RatelimitStore
+	routes map[string]string // route ID -> bucket key, learned from the X-RateLimit-Bucket header; see forget
+	global *ratelimitBucket  // Optional session-wide limit, acquired before every route bucket
+
+	// OnQueueDepth, if set, is called whenever the number of requests waiting on a bucket changes.
+	// It runs on the requesting goroutine, so keep it quick. Set it before making requests.
+	// Custom stores only report the global limit's queue.
+	OnQueueDepth func(bucket string, depth int)
 }
 
 func newRatelimiter() *Ratelimiter {
 	r := &Ratelimiter{
-		endpoints:     make(map[string]*ratelimitBucket),
-		CleanInterval: time.Minute,
-		stop:          make(chan struct{}),
+		routes: make(map[string]string),
 	}
 
-	go r.cleaner()
+	r.store = newMemoryRatelimitStore(time.Minute, r.reportQueueDepth, r.forget)
 	return r
 }
 
-// Close stops the background cleaner goroutine. Safe to call more than once.
+// forget drops the routes bound to evicted buckets, so that routes doesn't grow with every ID ever requested.
+// Until a route's next response names its bucket again, the route is its own bucket.
+func (r *Ratelimiter) forget(buckets []string) {
+	evicted := make(map[string]struct{}, len(buckets))
+	for _, key := range buckets {
+		evicted[key] = struct{}{}
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for id, key := range r.routes {
+		if _, gone := evicted[key]; gone {
+			delete(r.routes, id)
+		}
+	}
+}
+
+func (r *Ratelimiter) reportQueueDepth(bucket string, depth int) {
+	if r.OnQueueDepth != nil {
+		r.OnQueueDepth(bucket, depth)
+	}
+}
+
+// SetStore replaces where buckets are kept, e.g. with a FileRatelimitStore shared by several processes.
+// Call it before making requests. If the store implements io.Closer, Close closes it. Custom stores don't
+// report evicted buckets, so the routes learned for them are kept for the life of the session.
+func (r *Ratelimiter) SetStore(store RatelimitStore) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if closer, ok := r.store.(io.Closer); ok {
+		_ = closer.Close()
+	}
+
+	r.store = store
+}
+
+// Store returns the store buckets are kept in
+func (r *Ratelimiter) Store() RatelimitStore {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.store
+}
+
+// SetGlobalLimit caps the number of requests across all routes to "limit" per "window", on top of the
+// per-route buckets. A limit <= 0 removes the global limit. The global limit is always kept in memory.
+func (r *Ratelimiter) SetGlobalLimit(limit int, window time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if limit <= 0 || window <= 0 {
+		r.global = nil
+		return
+	}
+
+	r.global = &ratelimitBucket{
+		key:    "global",
+		state:  RatelimitState{Limit: limit, Remaining: limit},
+		window: window,
+	}
+}
+
+// Close closes the store if it implements io.Closer (the default store stops its background cleaner).
+// Safe to call more than once.
 func (r *Ratelimiter) Close() {
-	r.stopOnce.Do(func() { close(r.stop) })
+	if closer, ok := r.Store().(io.Closer); ok {
+		_ = closer.Close()
+	}
 }
 
-// https://developers.stoat.chat/developers/api/ratelimits
-// 		 /channels	15
-// POST	/channels/:id/messages	10
-// todo: check if ratelimits for /channels/:id/messages is per channel ID or global?
+/*
+	https://developers.stoat.chat/developers/api/ratelimits
+	Revolt ratelimits by route group, not by URL:
+			/users					20
+	PATCH	/users/:id				2
+			/bots					10
+			/channels				15
+	POST	/channels/:id/messages	10
+			/servers				5
+			/auth					15
+	DELETE	/auth					255
+			/safety					15
+			*						20
+
+	Until a response tells us the real group (X-RateLimit-Bucket), requests are bucketed by their route template
+	from endpoints.go, so IDs don't create a bucket each. Groups the API scopes to a resource (the major parameter;
+	a channel for messaging, a server, or the user being edited) keep that ID in their key.
+*/
+
+// ratelimitTemplates are the route templates requests are matched against, split into path segments.
+var ratelimitTemplates = func() [][]string {
+	templates := []string{
+		URLUser, URLUserMeUsername, URLUserMutual, URLUserDM, URLUserFlags, URLUserFriend, URLUserBlock,
+		URLUserProfile, URLUserDefaultAvatar,
+
+		URLServer, URLServerAck, URLServerChannels, URLServerMembers, URLServerRoles, URLServerRole,
+		URLServerPermissions, URLServerInvites, URLServerEmojis, URLServerRolesRanks, URLServerBans, URLServerBan,
+		URLServerMember,
 
-func (r *Ratelimiter) get(method, endpoint string) *ratelimitBucket {
+		URLChannel, URLChannelAckMessage, URLChannelJoinCall, URLChannelEndRing, URLChannelInvites,
+		URLChannelPermission, URLChannelRecipient, URLChannelSearch, URLChannelWebhooks, URLChannelMessages,
+		URLChannelMessage, URLChannelMembers, URLChannelMessageReactions, URLChannelMessageReaction,
+		URLChannelMessagePin,
+
+		URLWebhooks, URLWebhookToken, URLWebhookTokenGitHub, URLInvites, URLBots, URLBotInvite,
+		URLAuthMFA, URLAuthAccount, URLAuthSession, URLCustomEmoji, URLOnboard, URLSync, URLPush,
+		URLSafetyReport, URLPolicy,
+	}
+
+	split := make([][]string, len(templates))
+	for i, template := range templates {
+		split[i] = strings.Split(strings.Trim(template, "/"), "/")
+	}
+
+	return split
+}()
+
+// ratelimitRoute identifies which bucket a request belongs to before the API has told us its group.
+type ratelimitRoute struct {
+	template string // Method and route template, e.g. "POST:/channels/%s/messages"
+	major    string // ID the API scopes this route's bucket to, if any
+}
+
+// path returns the route template without its method, e.g. "/channels/%s/messages".
+func (r ratelimitRoute) path() string {
+	_, path, _ := strings.Cut(r.template, ":")
+	return path
+}
+
+// newRatelimitRoute derives the route of a request to an absolute endpoint URL, on the API at apiURL.
+func newRatelimitRoute(method, endpoint, apiURL string) ratelimitRoute {
 
 	// Strip query params without allocating memory (no string split)
 	if index := strings.IndexByte(endpoint, '?'); index >= 0 {
 		endpoint = endpoint[:index]
 	}
 
-	key := method + ":" + endpoint
+	// Only API routes have templates; anything else (e.g. the CDN) is bucketed by its URL
+	// A path-prefixed base (e.g. "/api") must not match endpoints that merely share its prefix (e.g. "/apiary")
+	path, isAPI := strings.CutPrefix(endpoint, apiURL)
+	if !isAPI || (path != "" && path[0] != '/') {
+		return ratelimitRoute{template: method + ":" + endpoint}
+	}
 
-	// Optimistic read-lock (cheap)
-	r.mu.RLock()
-	bucket, exists := r.endpoints[key]
-	r.mu.RUnlock()
+	segments := strings.Split(strings.Trim(path, "/"), "/")
 
-	// If bucket exists, return it
-	if exists {
-		return bucket
+	template := path
+	if matched := matchRatelimitTemplate(segments); matched != nil {
+		template = "/" + strings.Join(matched, "/")
 	}
 
-	// Bucket doesn't exist, upgrade to expensive lock (double-check locking pattern)
-	r.mu.Lock()
-	defer r.mu.Unlock()
+	return ratelimitRoute{
+		template: method + ":" + template,
+		major:    ratelimitMajorParameter(method, segments),
+	}
+}
+
+// matchRatelimitTemplate returns the most specific template (most literal segments) matching the path segments.
+func matchRatelimitTemplate(segments []string) (best []string) {
+	bestLiterals := -1
 
-	// Check again in case another goroutine created a bucket during the lock upgrade
-	if bucket, exists = r.endpoints[key]; exists {
-		return bucket
+	for _, template := range ratelimitTemplates {
+		if len(template) != len(segments) {
+			continue
+		}
+
+		literals := 0
+		for i, part := range template {
+			if part == "%s" {
+				continue
+			}
+
+			if part != segments[i] {
+				literals = -1
+				break
+			}
+
+			literals++
+		}
+
+		if literals > bestLiterals {
+			best, bestLiterals = template, literals
+		}
 	}
 
-	bucket = &ratelimitBucket{}
-	r.endpoints[key] = bucket
-	return bucket
+	return best
 }
 
-// update updates the ratelimit handler by populating the remaining and resetAfter fields
-func (b *ratelimitBucket) update(headers http.Header) error {
-	headerRemaining := headers.Get(ratelimitHeaderRemaining)
-	if headerRemaining == "" {
-		// If the header is missing, we can assume the rest of the ratelimit headers are missing too
-		return nil
+// ratelimitMajorParameter returns the ID the API scopes a route's bucket to, or "" if the bucket is shared.
+func ratelimitMajorParameter(method string, segments []string) string {
+	if len(segments) < 2 {
+		return ""
 	}
 
-	headerResetAfter := headers.Get(ratelimitHeaderResetAfter)
-	if headerResetAfter == "" {
-		return fmt.Errorf("missing %s header (remaining was present?)", ratelimitHeaderResetAfter)
+	switch segments[0] {
+	case "servers":
+		return segments[1]
+	case "channels":
+		if method == http.MethodPost && len(segments) == 3 && segments[2] == "messages" {
+			return segments[1]
+		}
+	case "users":
+		if method == http.MethodPatch {
+			return segments[1]
+		}
 	}
 
-	remaining, err := strconv.Atoi(headerRemaining)
-	if err != nil {
-		return err
+	return ""
+}
+
+// id is the default bucket key for the route, and the key its learned bucket is remembered under.
+func (route ratelimitRoute) id() string {
+	if route.major == "" {
+		return route.template
 	}
 
-	resetAfter, err := strconv.Atoi(headerResetAfter)
-	if err != nil {
-		return err
+	return route.template + ":" + route.major
+}
+
+// key returns the bucket key for the route: its learned group if known, otherwise the route itself.
+func (r *Ratelimiter) key(route ratelimitRoute) string {
+	id := route.id()
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if key, learned := r.routes[id]; learned {
+		return key
 	}
 
-	b.Lock()
-	b.remaining = remaining
-	b.resetAfter = time.Now().Add(time.Duration(resetAfter) * time.Millisecond)
-	b.Unlock()
+	return id
+}
 
-	return nil
+// wait queues for the global limit, then the route's bucket. It returns the route's bucket key once a request
+// may be sent.
+func (r *Ratelimiter) wait(ctx context.Context, route ratelimitRoute) (string, error) {
+	r.mu.RLock()
+	global := r.global
+	store := r.store
+	r.mu.RUnlock()
+
+	if global != nil {
+		if err := global.acquire(ctx, r.reportQueueDepth); err != nil {
+			return "", err
+		}
+	}
+
+	key := r.key(route)
+	return key, store.Wait(ctx, key)
 }
 
-// delay returns the time to wait before sending the request
-func (b *ratelimitBucket) delay() time.Duration {
-	b.Lock()
-	defer b.Unlock()
+// learn binds the route to the bucket named by the X-RateLimit-Bucket header, so that every route in the same
+// group shares one bucket. It returns the key the response's headers should be applied to.
+func (r *Ratelimiter) learn(route ratelimitRoute, headers http.Header) string {
+	name := headers.Get(ratelimitHeaderBucket)
+	if name == "" {
+		return r.key(route)
+	}
 
-	if b.remaining > 0 {
-		b.remaining--
-		return 0
+	key := "bucket:" + name
+	if route.major != "" {
+		key += ":" + route.major
 	}
 
-	wait := time.Until(b.resetAfter)
-	if wait < 0 {
-		return 0
+	id := route.id()
+
+	r.mu.RLock()
+	current := r.routes[id]
+	r.mu.RUnlock()
+
+	if current != key {
+		r.mu.Lock()
+		r.routes[id] = key
+		r.mu.Unlock()
 	}
 
-	return wait
+	return key
 }
 
-func (r *Ratelimiter) cleaner() {
-	ticker := time.NewTicker(r.CleanInterval)
-	defer ticker.Stop()
+// update applies the ratelimit headers of a response to the bucket. ok is false if the response had none.
+func (r *Ratelimiter) update(key string, headers http.Header) (ok bool, err error) {
+	state, ok, err := parseRatelimitHeaders(headers)
+	if !ok || err != nil {
+		return ok, err
+	}
 
-	for {
-		select {
-		case <-r.stop:
-			return
-		case <-ticker.C:
-			r.clean()
-		}
+	return true, r.Store().Update(key, state)
+}
+
+// release lets the next request through a bucket whose limit is unknown, after a request holding the others back
+// (see RatelimitState.take) ended without learning it: on a transport error, or a response without headers
+func (r *Ratelimiter) release(key string) {
+	store := r.Store()
+
+	state, known := store.Get(key)
+	if !known || state.Limit != 0 || state.Remaining != 0 || !time.Now().Before(state.Reset) {
+		return
 	}
+
+	state.Reset = time.Time{}
+	_ = store.Update(key, state)
 }
 
-func (r *Ratelimiter) clean() {
-	r.mu.Lock()
-	defer r.mu.Unlock()
+// block empties the route's bucket for at least "wait", e.g. after the API answered with 429
+func (r *Ratelimiter) block(route ratelimitRoute, wait time.Duration) error {
+	store := r.Store()
+	key := r.key(route)
 
-	now := time.Now()
-	for key, bucket := range r.endpoints {
+	state, _ := store.Get(key)
+	state.Remaining = 0
+	if until := time.Now().Add(wait); until.After(state.Reset) {
+		state.Reset = until
+	}
 
-		// Expired if bucket has received headers (not zero) and now is after resetAfter
-		bucket.Lock()
-		isExpired := !bucket.resetAfter.IsZero() && now.After(bucket.resetAfter)
-		bucket.Unlock()
+	return store.Update(key, state)
+}
 
-		if isExpired {
-			delete(r.endpoints, key)
-		}
+// parseRatelimitHeaders reads a response's ratelimit headers. ok is false if the response had none.
+func parseRatelimitHeaders(headers http.Header) (state RatelimitState, ok bool, err error) {
+	headerRemaining := headers.Get(ratelimitHeaderRemaining)
+	if headerRemaining == "" {
+		// If the header is missing, we can assume the rest of the ratelimit headers are missing too
+		return state, false, nil
 	}
+
+	headerResetAfter := headers.Get(ratelimitHeaderResetAfter)
+	if headerResetAfter == "" {
+		return state, false, fmt.Errorf("missing %s header (remaining was present?)", ratelimitHeaderResetAfter)
+	}
+
+	remaining, err := strconv.Atoi(headerRemaining)
+	if err != nil {
+		return state, false, err
+	}
+
+	resetAfter, err := strconv.Atoi(headerResetAfter)
+	if err != nil {
+		return state, false, err
+	}
+
+	// The limit is optional; it only lets us refill the bucket without waiting for the next response
+	limit, _ := strconv.Atoi(headers.Get(ratelimitHeaderLimit))
+
+	state = RatelimitState{
+		Limit:     limit,
+		Remaining: remaining,
+		Reset:     time.Now().Add(time.Duration(resetAfter) * time.Millisecond),
+	}
+
+	return state, true, nil
 }
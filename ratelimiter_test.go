@@ -0,0 +1,127 @@
+package revoltgo_test
+
+import (
+	"net/http"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/sentinelb51/revoltgo"
+	"github.com/sentinelb51/revoltgo/revolttest"
+)
+
+// TestRatelimitWithoutHeaders makes requests in a row on a route whose responses report no ratelimit;
+// the request learning the limit must not hold back the next one once it is done.
+func TestRatelimitWithoutHeaders(t *testing.T) {
+	server := revolttest.NewServer()
+	defer server.Close()
+
+	server.Ratelimits = nil
+
+	session := server.Session("token")
+
+	for i := range 4 {
+		start := time.Now()
+		if _, err := session.User("@me"); err != nil {
+			t.Fatal(err)
+		}
+
+		if took := time.Since(start); took > time.Second {
+			t.Fatalf("request %d took %v", i, took)
+		}
+	}
+}
+
+// TestRatelimitHeaders exhausts a bucket; the requests over its limit must wait for it to reset instead of being
+// rejected with 429 Too Many Requests.
+func TestRatelimitHeaders(t *testing.T) {
+	const window = 300 * time.Millisecond
+
+	server := revolttest.NewServer()
+	defer server.Close()
+
+	server.Ratelimits["users"] = revolttest.Ratelimit{Limit: 2, Window: window}
+
+	session := server.Session("token")
+
+	start := time.Now()
+	for range 5 {
+		if _, err := session.User("@me"); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Two requests per window: the third and fifth requests each waited for a new window
+	if took := time.Since(start); took < 2*window {
+		t.Fatalf("5 requests took %v, faster than the limit allows", took)
+	}
+
+	if n := len(server.Requests()); n != 5 {
+		t.Fatalf("sent %d requests for 5 calls; some were rejected", n)
+	}
+}
+
+// TestRatelimitTooManyRequests answers with 429 once; the request must be retried after the time it asked for.
+func TestRatelimitTooManyRequests(t *testing.T) {
+	const retryAfter = 200 * time.Millisecond
+
+	server := revolttest.NewServer()
+	defer server.Close()
+
+	var calls atomic.Int32
+	server.Handle("GET /users/{id}", func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) > 1 {
+			revolttest.JSON(http.StatusOK, server.Self)(w, r)
+			return
+		}
+
+		ms := retryAfter.Milliseconds()
+		w.Header().Set("X-RateLimit-Remaining", "0")
+		w.Header().Set("X-RateLimit-Reset-After", strconv.FormatInt(ms, 10))
+		revolttest.JSON(http.StatusTooManyRequests, map[string]int64{"retry_after": ms})(w, r)
+	})
+
+	session := server.Session("token")
+
+	start := time.Now()
+	user, err := session.User("@me")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if user.ID != server.Self.ID {
+		t.Fatalf("user is %+v", user)
+	}
+
+	if took := time.Since(start); took < retryAfter {
+		t.Fatalf("retried after %v, want at least %v", took, retryAfter)
+	}
+
+	if n := calls.Load(); n != 2 {
+		t.Fatalf("sent %d requests, want 2", n)
+	}
+}
+
+// TestRatelimitPerChannel sends messages to two channels; the messaging bucket of one must not hold back the other.
+func TestRatelimitPerChannel(t *testing.T) {
+	const window = time.Minute
+
+	server := revolttest.NewServer()
+	defer server.Close()
+
+	server.Ratelimits["messaging"] = revolttest.Ratelimit{Limit: 1, Window: window}
+
+	session := server.Session("token")
+
+	for _, channel := range []string{"general", "random"} {
+		start := time.Now()
+		if _, err := session.ChannelMessageSend(channel, revoltgo.MessageSend{Content: "hello"}); err != nil {
+			t.Fatal(err)
+		}
+
+		if took := time.Since(start); took > time.Second {
+			t.Fatalf("sending to %s took %v", channel, took)
+		}
+	}
+}
@@ -0,0 +1,304 @@
+package revoltgo
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+type ratelimitBucket struct {
+	sync.Mutex
+	key   string // Bucket key, reported to Ratelimiter.OnQueueDepth
+	state RatelimitState
+
+	// window is set for buckets we pace ourselves (the global limit); others learn their window from headers
+	window time.Duration
+
+	// queue holds the waiters in arrival order. The head's channel is closed, and only the head waits for the
+	// bucket to refill; everyone behind it sleeps until it hands over, so a refill never wakes a stampede.
+	queue []chan struct{}
+
+	// updated is closed (and replaced) whenever new headers arrive, to wake the head as soon as they change its wait
+	updated chan struct{}
+
+	// users counts the Wait and Update calls holding the bucket, from get to release, so the cleaner leaves it be
+	users atomic.Int32
+}
+
+// release hands back a bucket returned by memoryRatelimitStore.get
+func (b *ratelimitBucket) release() {
+	b.users.Add(-1)
+}
+
+// set replaces the bucket's state with what a response reported
+func (b *ratelimitBucket) set(state RatelimitState) {
+	b.Lock()
+	defer b.Unlock()
+
+	b.state = state
+	b.notify()
+}
+
+// notify wakes a head waiting for new headers. The caller must hold the lock.
+func (b *ratelimitBucket) notify() {
+	if b.updated != nil {
+		close(b.updated)
+		b.updated = nil
+	}
+}
+
+// take consumes a request from the bucket if one is available. The caller must hold the lock.
+func (b *ratelimitBucket) take() bool {
+	now := time.Now()
+
+	if !b.state.take(now) {
+		return false
+	}
+
+	// Self-paced buckets open their window on the first request in it
+	if b.window > 0 && b.state.Reset.IsZero() {
+		b.state.Reset = now.Add(b.window)
+	}
+
+	return true
+}
+
+// acquire blocks until the bucket allows a request, in first-come first-served order.
+// onDepth (optional) is told how many requests are queued whenever that changes.
+func (b *ratelimitBucket) acquire(ctx context.Context, onDepth func(bucket string, depth int)) error {
+	b.Lock()
+
+	// Fast path: nobody is queued and there is room
+	if len(b.queue) == 0 && b.take() {
+		b.Unlock()
+		return nil
+	}
+
+	turn := make(chan struct{})
+	b.queue = append(b.queue, turn)
+	if len(b.queue) == 1 {
+		close(turn) // Nobody ahead of us
+	}
+	key, depth := b.key, len(b.queue)
+	b.Unlock()
+
+	if onDepth != nil {
+		onDepth(key, depth)
+	}
+
+	err := b.awaitTurn(ctx, turn)
+
+	b.Lock()
+	b.dequeue(turn)
+	key, depth = b.key, len(b.queue)
+	b.Unlock()
+
+	if onDepth != nil {
+		onDepth(key, depth)
+	}
+
+	return err
+}
+
+// awaitTurn waits until "turn" reaches the head of the queue, then until the bucket has room.
+func (b *ratelimitBucket) awaitTurn(ctx context.Context, turn chan struct{}) error {
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-turn:
+	}
+
+	for {
+		b.Lock()
+		if b.take() {
+			b.Unlock()
+			return nil
+		}
+
+		// Wait for the window to end, or for new headers (e.g. the limit a request in flight learns)
+		if b.updated == nil {
+			b.updated = make(chan struct{})
+		}
+		updated := b.updated
+		wait := time.Until(b.state.Reset)
+		b.Unlock()
+
+		// The window is used up but its reset is unknown until a request in flight responds
+		unknown := wait <= 0
+		if unknown {
+			wait = ratelimitUnknownResetTimeout
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-updated:
+			timer.Stop()
+		case <-timer.C:
+			if unknown {
+				return nil // The response never came (e.g. a transport error); don't wait forever
+			}
+		}
+	}
+}
+
+// dequeue removes "turn" from the queue, handing the head over to the next waiter if "turn" was the head.
+// The caller must hold the lock.
+func (b *ratelimitBucket) dequeue(turn chan struct{}) {
+	index := slices.Index(b.queue, turn)
+	if index < 0 {
+		return
+	}
+
+	b.queue = slices.Delete(b.queue, index, index+1)
+	if index == 0 && len(b.queue) > 0 {
+		close(b.queue[0])
+	}
+}
+
+// memoryRatelimitStore is the default RatelimitStore. Requests on a bucket are released in FIFO order.
+type memoryRatelimitStore struct {
+	mu      sync.RWMutex
+	buckets map[string]*ratelimitBucket
+
+	onDepth func(bucket string, depth int)
+	// onEvict is told which buckets the cleaner removed
+	onEvict func(buckets []string)
+
+	// Interval to clean-up stale ratelimit buckets.
+	cleanInterval time.Duration
+	// stop the background cleaner
+	stop     chan struct{}
+	stopOnce sync.Once
+}
+
+func newMemoryRatelimitStore(cleanInterval time.Duration, onDepth func(bucket string, depth int), onEvict func(buckets []string)) *memoryRatelimitStore {
+	s := &memoryRatelimitStore{
+		buckets:       make(map[string]*ratelimitBucket),
+		onDepth:       onDepth,
+		onEvict:       onEvict,
+		cleanInterval: cleanInterval,
+		stop:          make(chan struct{}),
+	}
+
+	go s.cleaner()
+	return s
+}
+
+// get returns the bucket for key, creating it if needed. The bucket is counted as in use, and kept from the
+// cleaner, until released; the count is taken under s.mu, so the cleaner never removes a bucket get returned.
+func (s *memoryRatelimitStore) get(key string) *ratelimitBucket {
+
+	// Optimistic read-lock (cheap)
+	s.mu.RLock()
+	bucket, exists := s.buckets[key]
+	if exists {
+		bucket.users.Add(1)
+	}
+	s.mu.RUnlock()
+
+	// If bucket exists, return it
+	if exists {
+		return bucket
+	}
+
+	// Bucket doesn't exist, upgrade to expensive lock (double-check locking pattern)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// Check again in case another goroutine created a bucket during the lock upgrade
+	if bucket, exists = s.buckets[key]; !exists {
+		bucket = &ratelimitBucket{key: key}
+		s.buckets[key] = bucket
+	}
+
+	bucket.users.Add(1)
+	return bucket
+}
+
+func (s *memoryRatelimitStore) Wait(ctx context.Context, bucket string) error {
+	b := s.get(bucket)
+	defer b.release()
+
+	return b.acquire(ctx, s.onDepth)
+}
+
+func (s *memoryRatelimitStore) Update(bucket string, state RatelimitState) error {
+	b := s.get(bucket)
+	defer b.release()
+
+	b.set(state)
+	return nil
+}
+
+func (s *memoryRatelimitStore) Get(bucket string) (RatelimitState, bool) {
+	s.mu.RLock()
+	b, exists := s.buckets[bucket]
+	s.mu.RUnlock()
+
+	if !exists {
+		return RatelimitState{}, false
+	}
+
+	b.Lock()
+	defer b.Unlock()
+	return b.state, true
+}
+
+// Close stops the background cleaner goroutine. Safe to call more than once.
+func (s *memoryRatelimitStore) Close() error {
+	s.stopOnce.Do(func() { close(s.stop) })
+	return nil
+}
+
+func (s *memoryRatelimitStore) cleaner() {
+	ticker := time.NewTicker(s.cleanInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.stop:
+			return
+		case <-ticker.C:
+			s.clean()
+		}
+	}
+}
+
+func (s *memoryRatelimitStore) clean() {
+	evicted := s.evict()
+
+	if len(evicted) > 0 && s.onEvict != nil {
+		s.onEvict(evicted)
+	}
+}
+
+// evict removes the expired buckets, and returns their keys.
+func (s *memoryRatelimitStore) evict() (evicted []string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	for key, bucket := range s.buckets {
+
+		// Expired if bucket has received headers (not zero) and now is after the reset,
+		// or if it was refilled from its known limit and hasn't been used since
+		bucket.Lock()
+		state := bucket.state
+		isExpired := !state.Reset.IsZero() && now.After(state.Reset) ||
+			state.Reset.IsZero() && state.Limit > 0 && state.Remaining == state.Limit
+		isExpired = isExpired && len(bucket.queue) == 0 && bucket.users.Load() == 0
+		bucket.Unlock()
+
+		if isExpired {
+			delete(s.buckets, key)
+			evicted = append(evicted, key)
+		}
+	}
+
+	return evicted
+}
@@ -0,0 +1,169 @@
+//go:build linux || darwin || freebsd || netbsd || openbsd || dragonfly
+
+package revoltgo
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"syscall"
+	"time"
+
+	"github.com/goccy/go-json"
+)
+
+// FileRatelimitStore is a reference RatelimitStore for processes on the same machine sharing one token.
+// Each bucket is a small file in a directory, locked with flock(2) while it is read and written, so every
+// process spends from the same budget:
+//
+//	store, err := revoltgo.NewFileRatelimitStore(filepath.Join(os.TempDir(), "revoltgo-ratelimits"))
+//	if err != nil {
+//		// handle
+//	}
+//	session.HTTP.SetRatelimitStore(store)
+//
+// Unlike the default store, waiters across processes are not released in strict FIFO order.
+type FileRatelimitStore struct {
+	dir string
+
+	// PollInterval is how often a waiter re-checks a bucket whose reset time is unknown,
+	// i.e. while another process's request is still in flight.
+	PollInterval time.Duration
+}
+
+// fileRatelimitState is the on-disk form of RatelimitState
+type fileRatelimitState struct {
+	Limit     int   `json:"limit"`
+	Remaining int   `json:"remaining"`
+	Reset     int64 `json:"reset"` // Unix milliseconds; 0 if unknown
+}
+
+// NewFileRatelimitStore creates the directory if needed. Every process must use the same directory.
+func NewFileRatelimitStore(dir string) (*FileRatelimitStore, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("ratelimit store: %w", err)
+	}
+
+	return &FileRatelimitStore{dir: dir, PollInterval: 100 * time.Millisecond}, nil
+}
+
+func (s *FileRatelimitStore) Wait(ctx context.Context, bucket string) error {
+	var unknownSince time.Time
+
+	for {
+		var (
+			taken bool
+			limit int
+			wait  time.Duration
+		)
+
+		err := s.locked(bucket, func(state *RatelimitState) bool {
+			taken = state.take(time.Now())
+			limit = state.Limit
+			wait = time.Until(state.Reset)
+			return taken
+		})
+
+		if err != nil || taken {
+			return err
+		}
+
+		if wait > 0 {
+			unknownSince = time.Time{}
+
+			// A request in flight is learning the limit, and may report it well before the window ends
+			if limit == 0 {
+				wait = min(wait, s.PollInterval)
+			}
+		} else {
+			// Used up, with the reset unknown until another request responds; poll rather than guess
+			if unknownSince.IsZero() {
+				unknownSince = time.Now()
+			} else if time.Since(unknownSince) > ratelimitUnknownResetTimeout {
+				return nil // The response never came; don't wait forever
+			}
+
+			wait = s.PollInterval
+		}
+
+		if err = sleepContext(ctx, wait); err != nil {
+			return err
+		}
+	}
+}
+
+func (s *FileRatelimitStore) Update(bucket string, state RatelimitState) error {
+	return s.locked(bucket, func(current *RatelimitState) bool {
+		*current = state
+		return true
+	})
+}
+
+func (s *FileRatelimitStore) Get(bucket string) (state RatelimitState, known bool) {
+	err := s.locked(bucket, func(current *RatelimitState) bool {
+		state = *current
+		known = *current != RatelimitState{}
+		return false
+	})
+
+	return state, err == nil && known
+}
+
+// locked runs fn with the bucket's state while holding an exclusive lock on its file.
+// If fn returns true, the (modified) state is written back.
+func (s *FileRatelimitStore) locked(bucket string, fn func(state *RatelimitState) bool) error {
+	path := filepath.Join(s.dir, url.PathEscape(bucket))
+
+	file, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0o600)
+	if err != nil {
+		return fmt.Errorf("ratelimit store: %w", err)
+	}
+	defer file.Close()
+
+	// Closing the file releases the lock
+	if err = syscall.Flock(int(file.Fd()), syscall.LOCK_EX); err != nil {
+		return fmt.Errorf("ratelimit store: flock: %w", err)
+	}
+
+	data, err := io.ReadAll(file)
+	if err != nil {
+		return fmt.Errorf("ratelimit store: %w", err)
+	}
+
+	// An empty (new) or unreadable file is an unknown bucket
+	var stored fileRatelimitState
+	if len(data) > 0 {
+		_ = json.Unmarshal(data, &stored)
+	}
+
+	state := RatelimitState{Limit: stored.Limit, Remaining: stored.Remaining}
+	if stored.Reset != 0 {
+		state.Reset = time.UnixMilli(stored.Reset)
+	}
+
+	if !fn(&state) {
+		return nil
+	}
+
+	stored = fileRatelimitState{Limit: state.Limit, Remaining: state.Remaining}
+	if !state.Reset.IsZero() {
+		stored.Reset = state.Reset.UnixMilli()
+	}
+
+	if data, err = json.Marshal(stored); err != nil {
+		return fmt.Errorf("ratelimit store: %w", err)
+	}
+
+	if err = file.Truncate(0); err != nil {
+		return fmt.Errorf("ratelimit store: %w", err)
+	}
+
+	if _, err = file.WriteAt(data, 0); err != nil {
+		return fmt.Errorf("ratelimit store: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,70 @@
+package revoltgo
+
+import (
+	"slices"
+	"time"
+)
+
+// ReconnectPolicy controls how the Websocket reconnects after losing its connection.
+//
+// Delays grow exponentially and are randomised by Jitter, so that clients disconnected by the same outage
+// don't all reconnect at the same moment. Some failures are fatal and never retried: an invalid token will
+// stay invalid no matter how often we reconnect with it.
+type ReconnectPolicy struct {
+	// MaxAttempts is how many reconnection attempts are made after a disconnection. Zero retries forever
+	MaxAttempts int
+
+	// BaseDelay is the delay before the first attempt; it doubles on every following attempt
+	BaseDelay time.Duration
+
+	// MaxDelay caps a single delay, if positive
+	MaxDelay time.Duration
+
+	// Jitter is the fraction (0 to 1) of every delay that is randomised; 0.5 waits between 50% and 100% of it
+	Jitter float64
+
+	// Backoff optionally overrides the exponential curve, including jitter. attempt starts at 1
+	Backoff func(attempt int) time.Duration
+
+	// FatalCloseCodes are close codes that mean reconnecting won't help
+	FatalCloseCodes []uint16
+
+	// FatalErrors are EventError types that mean reconnecting won't help
+	FatalErrors []EventErrorDataType
+
+	// OnGiveUp is called once the Websocket stops reconnecting, with the reason. The Session must be opened again
+	OnGiveUp func(err error)
+}
+
+// DefaultReconnectPolicy retries forever, backing off from 1 second up to 2 minutes, except for invalid sessions.
+func DefaultReconnectPolicy() ReconnectPolicy {
+	return ReconnectPolicy{
+		BaseDelay: time.Second,
+		MaxDelay:  2 * time.Minute,
+		Jitter:    0.5,
+		FatalCloseCodes: []uint16{
+			1008, // Policy violation
+		},
+		FatalErrors: []EventErrorDataType{
+			EventErrorInvalidSession,
+			EventErrorOnboardingNotFinished,
+		},
+	}
+}
+
+// delay returns how long to wait before the given attempt (1 for the first).
+func (p *ReconnectPolicy) delay(attempt int) time.Duration {
+	if p.Backoff != nil {
+		return p.Backoff(attempt)
+	}
+
+	return backoff(p.BaseDelay, p.MaxDelay, attempt, p.Jitter)
+}
+
+func (p *ReconnectPolicy) fatalCloseCode(code uint16) bool {
+	return slices.Contains(p.FatalCloseCodes, code)
+}
+
+func (p *ReconnectPolicy) fatalError(errorType EventErrorDataType) bool {
+	return slices.Contains(p.FatalErrors, errorType)
+}
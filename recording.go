@@ -0,0 +1,241 @@
+package revoltgo
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/tinylib/msgp/msgp"
+)
+
+/*
+	A recording is the magic header, followed by a record per frame:
+	the time it was received (int64 Unix nanoseconds), the frame's length (uint32), and the frame itself.
+	Integers are little-endian, like the heartbeat payloads.
+*/
+
+const (
+	recordingMagic      = "revoltgo-recording/1\n"
+	recordingHeaderSize = 8 + 4
+
+	// maxRecordedFrameSize bounds the frames of a recording, well above anything the Websocket accepts,
+	// so that a corrupt length cannot make RecordingReader allocate gigabytes
+	maxRecordedFrameSize = 64 << 20
+)
+
+// Recorder writes the raw frames received from the Websocket to a recording, to be replayed with Session.Replay
+// or dumped as JSON Lines with DumpRecording. Set it on Session.Recorder before opening the session:
+//
+//	file, _ := os.Create("events.rec")
+//	defer file.Close()
+//
+//	session.Recorder = revoltgo.NewRecorder(file)
+//
+// Frames are written in the order they are received, before being handled, even with DispatchParallel;
+// wrap w in a bufio.Writer to batch writes.
+// Recordings hold everything the account can see, including message contents, so store them accordingly.
+type Recorder struct {
+	mu      sync.Mutex
+	w       io.Writer
+	started bool
+	buf     []byte
+}
+
+// NewRecorder returns a Recorder writing to w.
+func NewRecorder(w io.Writer) *Recorder {
+	return &Recorder{w: w}
+}
+
+// Record writes a frame received at the current time. It is safe for concurrent use.
+func (r *Recorder) Record(frame []byte) error {
+	return r.RecordAt(time.Now(), frame)
+}
+
+// RecordAt writes a frame received at the given time, e.g. to build a recording by hand.
+func (r *Recorder) RecordAt(at time.Time, frame []byte) error {
+	if len(frame) > maxRecordedFrameSize {
+		return fmt.Errorf("frame too large: %d bytes", len(frame))
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.buf = r.buf[:0]
+	if !r.started {
+		r.buf = append(r.buf, recordingMagic...)
+	}
+
+	r.buf = binary.LittleEndian.AppendUint64(r.buf, uint64(at.UnixNano()))
+	r.buf = binary.LittleEndian.AppendUint32(r.buf, uint32(len(frame)))
+	r.buf = append(r.buf, frame...)
+
+	// One write per record, so that a failed write never leaves half a header behind a good frame
+	if _, err := r.w.Write(r.buf); err != nil {
+		return err
+	}
+
+	r.started = true
+	return nil
+}
+
+// RecordedFrame is a frame read from a recording.
+type RecordedFrame struct {
+	Time time.Time // When the frame was received
+	Data []byte    // The msgpack frame, as received
+}
+
+// RecordingReader reads the frames of a recording written by a Recorder.
+type RecordingReader struct {
+	r       io.Reader
+	started bool
+}
+
+// NewRecordingReader returns a RecordingReader reading from r.
+func NewRecordingReader(r io.Reader) *RecordingReader {
+	return &RecordingReader{r: r}
+}
+
+// Next returns the next frame of the recording, or io.EOF once there are none left.
+// A recording that ends in the middle of a frame returns io.ErrUnexpectedEOF, and one with a frame
+// larger than 64 MiB, which no Recorder writes, returns an error instead of allocating it.
+func (r *RecordingReader) Next() (frame RecordedFrame, err error) {
+	if !r.started {
+		magic := make([]byte, len(recordingMagic))
+		if _, err = io.ReadFull(r.r, magic); err != nil {
+			if errors.Is(err, io.ErrUnexpectedEOF) {
+				err = fmt.Errorf("not a recording: %w", err)
+			}
+			return
+		}
+
+		if string(magic) != recordingMagic {
+			return frame, fmt.Errorf("not a recording: bad magic %q", magic)
+		}
+
+		r.started = true
+	}
+
+	var header [recordingHeaderSize]byte
+	if _, err = io.ReadFull(r.r, header[:]); err != nil {
+		return
+	}
+
+	size := binary.LittleEndian.Uint32(header[8:])
+	if size > maxRecordedFrameSize {
+		return frame, fmt.Errorf("frame too large: %d bytes", size)
+	}
+
+	frame.Time = time.Unix(0, int64(binary.LittleEndian.Uint64(header[:8])))
+	frame.Data = make([]byte, size)
+
+	if _, err = io.ReadFull(r.r, frame.Data); errors.Is(err, io.EOF) {
+		err = io.ErrUnexpectedEOF
+	}
+
+	return
+}
+
+// Replay handles the frames of a recording as if they were received from the Websocket, without any network,
+// so that State and handlers can be exercised against real traffic. The session must not be connected.
+// Like Open, it takes an optional StateConfig deciding what the State tracks.
+//
+// speed scales the time between frames: 1 replays in real time, 10 ten times faster, and zero (or less)
+// replays as fast as possible. Replay returns once every frame is handled, or ctx is done, and in either
+// case only after the handlers of the frames replayed so far have returned, whatever the Session.DispatchMode.
+//
+// Handlers making REST calls still reach the API, so register them with care, or point the session at a
+// revolttest server.
+func (s *Session) Replay(ctx context.Context, recording io.Reader, speed float64, configuration ...StateConfig) error {
+
+	if s.IsConnected() {
+		return fmt.Errorf("already connected")
+	}
+
+	config := DefaultStateConfig()
+	if len(configuration) > 0 {
+		config = configuration[0]
+	}
+
+	s.State.applyConfig(config)
+	s.addDefaultHandlers()
+
+	s.WS = newWebsocket(s, "")
+	s.WS.ShouldReconnect = false
+
+	// Stop the dispatcher like a closed Websocket would, once the handlers it started are done
+	defer func() {
+		s.WS.cancel()
+		s.WS.dispatcher.wait()
+	}()
+
+	reader := NewRecordingReader(recording)
+
+	var previous time.Time
+	for {
+		frame, err := reader.Next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+
+		if err != nil {
+			return err
+		}
+
+		if speed > 0 && !previous.IsZero() {
+			if wait := time.Duration(float64(frame.Time.Sub(previous)) / speed); wait > 0 {
+				timer := time.NewTimer(wait)
+				select {
+				case <-ctx.Done():
+					timer.Stop()
+					return ctx.Err()
+				case <-timer.C:
+				}
+			}
+		}
+
+		if err = ctx.Err(); err != nil {
+			return err
+		}
+
+		previous = frame.Time
+		s.WS.handle(frame.Data)
+	}
+}
+
+// DumpRecording writes the frames of a recording to w as JSON Lines, one {"time":...,"event":...} object per frame.
+func DumpRecording(w io.Writer, recording io.Reader) error {
+	reader := NewRecordingReader(recording)
+
+	var line bytes.Buffer
+	for {
+		frame, err := reader.Next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+
+		if err != nil {
+			return err
+		}
+
+		line.Reset()
+		line.WriteString(`{"time":`)
+		line.WriteString(strconv.Quote(frame.Time.UTC().Format(time.RFC3339Nano)))
+		line.WriteString(`,"event":`)
+
+		if _, err = msgp.CopyToJSON(&line, bytes.NewReader(frame.Data)); err != nil {
+			return fmt.Errorf("frame at %s: %w", frame.Time.Format(time.RFC3339Nano), err)
+		}
+
+		line.WriteString("}\n")
+
+		if _, err = w.Write(line.Bytes()); err != nil {
+			return err
+		}
+	}
+}
@@ -0,0 +1,129 @@
+package revoltgo
+
+import (
+	"math"
+	"math/rand/v2"
+	"net/http"
+	"slices"
+	"strconv"
+	"time"
+)
+
+// RetryPolicy controls how HTTPClient retries failed requests.
+//
+// 429 (Too Many Requests) is retried for every method, because the API rejected the request before processing it.
+// Other StatusCodes and transport errors are only retried for Methods, since repeating a non-idempotent request
+// (e.g. sending a message) after the server may have processed it could duplicate it.
+// Uploads are only retried if FileParams.Reader implements io.Seeker, so it can be rewound.
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts, including the first. Values <= 1 disable retries
+	MaxAttempts int
+
+	// BaseDelay is the backoff before the first retry; it doubles on every following retry
+	BaseDelay time.Duration
+
+	// MaxDelay caps a single backoff delay, if positive. It does not cap the server's 429 retry hint
+	MaxDelay time.Duration
+
+	// Backoff optionally overrides the exponential curve. attempt starts at 1 for the first retry
+	Backoff func(attempt int) time.Duration
+
+	// StatusCodes that are considered transient
+	StatusCodes []int
+
+	// Methods that are idempotent enough to be retried after a transient failure
+	Methods []string
+}
+
+// DefaultRetryPolicy retries ratelimits and transient server errors up to 3 times in total.
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts: 3,
+		BaseDelay:   500 * time.Millisecond,
+		MaxDelay:    10 * time.Second,
+		StatusCodes: []int{
+			http.StatusTooManyRequests,
+			http.StatusInternalServerError,
+			http.StatusBadGateway,
+			http.StatusServiceUnavailable,
+			http.StatusGatewayTimeout,
+		},
+		Methods: []string{
+			http.MethodGet,
+			http.MethodHead,
+			http.MethodOptions,
+			http.MethodPut,
+			http.MethodDelete,
+		},
+	}
+}
+
+// shouldRetry reports whether a request that failed on "attempt" is worth repeating.
+// A statusCode of 0 means the request failed before a response was received.
+func (p *RetryPolicy) shouldRetry(attempt int, method string, statusCode int) bool {
+	if attempt >= p.MaxAttempts {
+		return false
+	}
+
+	if statusCode == http.StatusTooManyRequests {
+		return slices.Contains(p.StatusCodes, statusCode)
+	}
+
+	if !slices.Contains(p.Methods, method) {
+		return false
+	}
+
+	return statusCode == 0 || slices.Contains(p.StatusCodes, statusCode)
+}
+
+// delay returns the backoff before the given retry (1 for the first retry).
+func (p *RetryPolicy) delay(attempt int) time.Duration {
+	if p.Backoff != nil {
+		return p.Backoff(attempt)
+	}
+
+	return backoff(p.BaseDelay, p.MaxDelay, attempt, 0)
+}
+
+// backoff doubles base for every attempt after the first, capped at maxDelay if positive.
+// jitter (0 to 1) then subtracts up to that fraction of the delay at random, so clients don't retry in lockstep.
+func backoff(base, maxDelay time.Duration, attempt int, jitter float64) time.Duration {
+
+	// Doubling stops early at the cap, or before overflowing when there is none
+	delay := base
+	for i := 1; i < attempt && delay <= math.MaxInt64/2; i++ {
+		delay *= 2
+		if maxDelay > 0 && delay >= maxDelay {
+			break
+		}
+	}
+
+	if maxDelay > 0 && delay > maxDelay {
+		delay = maxDelay
+	}
+
+	if jitter = min(max(jitter, 0), 1); jitter > 0 && delay > 0 {
+		spread := time.Duration(float64(delay) * jitter)
+		delay -= rand.N(spread + 1)
+	}
+
+	return delay
+}
+
+// retryAfter extracts the server's retry hint from a 429 response.
+// Revolt sends X-RateLimit-Reset-After in milliseconds; the standard Retry-After (seconds) is the fallback.
+func retryAfter(headers http.Header) time.Duration {
+	if value := headers.Get(ratelimitHeaderResetAfter); value != "" {
+		if ms, err := strconv.Atoi(value); err == nil {
+			return time.Duration(ms) * time.Millisecond
+		}
+	}
+
+	if value := headers.Get("Retry-After"); value != "" {
+		if seconds, err := strconv.Atoi(value); err == nil {
+			return time.Duration(seconds) * time.Second
+		}
+	}
+
+	return 0
+}
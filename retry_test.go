@@ -0,0 +1,89 @@
+package revoltgo_test
+
+import (
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/sentinelb51/revoltgo"
+	"github.com/sentinelb51/revoltgo/revolttest"
+)
+
+// retryPolicy is the default policy, without the delays.
+func retryPolicy() revoltgo.RetryPolicy {
+	policy := revoltgo.DefaultRetryPolicy()
+	policy.BaseDelay = time.Millisecond
+	return policy
+}
+
+func TestRetryTransientErrors(t *testing.T) {
+	server := revolttest.NewServer()
+	defer server.Close()
+
+	var calls atomic.Int32
+	server.Handle("GET /users/{id}", func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) < 3 {
+			revolttest.JSON(http.StatusBadGateway, map[string]string{"type": "InternalError"})(w, r)
+			return
+		}
+
+		revolttest.JSON(http.StatusOK, server.Self)(w, r)
+	})
+
+	session := server.Session("token")
+	session.HTTP.SetRetryPolicy(retryPolicy())
+
+	if _, err := session.User("@me"); err != nil {
+		t.Fatal(err)
+	}
+
+	if n := calls.Load(); n != 3 {
+		t.Fatalf("sent %d requests, want 3", n)
+	}
+}
+
+func TestRetryGivesUp(t *testing.T) {
+	server := revolttest.NewServer()
+	defer server.Close()
+
+	var calls atomic.Int32
+	server.Handle("GET /users/{id}", func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		revolttest.JSON(http.StatusServiceUnavailable, map[string]string{"type": "InternalError"})(w, r)
+	})
+
+	session := server.Session("token")
+	session.HTTP.SetRetryPolicy(retryPolicy())
+
+	if _, err := session.User("@me"); err == nil {
+		t.Fatal("no error once every attempt failed")
+	}
+
+	if n, want := calls.Load(), int32(retryPolicy().MaxAttempts); n != want {
+		t.Fatalf("sent %d requests, want %d", n, want)
+	}
+}
+
+// TestRetryNotIdempotent fails to send a message; it must not be sent again, since the server may have sent it.
+func TestRetryNotIdempotent(t *testing.T) {
+	server := revolttest.NewServer()
+	defer server.Close()
+
+	var calls atomic.Int32
+	server.Handle("POST /channels/{id}/messages", func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		revolttest.JSON(http.StatusBadGateway, map[string]string{"type": "InternalError"})(w, r)
+	})
+
+	session := server.Session("token")
+	session.HTTP.SetRetryPolicy(retryPolicy())
+
+	if _, err := session.ChannelMessageSend("general", revoltgo.MessageSend{Content: "hello"}); err == nil {
+		t.Fatal("no error sending the message")
+	}
+
+	if n := calls.Load(); n != 1 {
+		t.Fatalf("sent the message %d times, want once", n)
+	}
+}
@@ -6,14 +6,12 @@ Package revoltgo is a wrapper for the Revolt API with low-level bindings
 		To compile correctly, always run beforehand:
 			/tools/msgp_codegen.py  (ensures all msgp code is generated: revoltgo_msgp_gen.go)
 			/tools/build_hash.py    (updates the COMMIT variable in this file)
-
-	   Todo: do we need state.go to track VoiceStates?
 */
 
 package revoltgo
 
 import (
-	"log"
+	"log/slog"
 	"net/http"
 	"time"
 
@@ -45,10 +43,15 @@ type GithubReposCommitUserData struct {
 	Date time.Time `json:"date"`
 }
 
+// HasUpdate checks GitHub for a newer commit of the library, logging the result to slog.Default().
 func HasUpdate() bool {
+	return hasUpdate(slog.Default())
+}
+
+func hasUpdate(logger *slog.Logger) bool {
 	response, err := http.Get(MainCommitsURL)
 	if err != nil {
-		log.Printf("Update check failed whilst fetching: %v", err)
+		logger.Warn("Update check failed whilst fetching", "err", err)
 		return false
 	}
 
@@ -57,22 +60,22 @@ func HasUpdate() bool {
 	var repo GithubRepos
 	err = json.NewDecoder(response.Body).Decode(&repo)
 	if err != nil {
-		log.Printf("Update check failed whilst decoding: %v", err)
+		logger.Warn("Update check failed whilst decoding", "err", err)
 		return false
 	}
 
 	if repo.Sha != COMMIT {
 		days := time.Now().Sub(repo.Commits.Author.Date).Hours() / 24
-		log.Printf("A new nightly update is available (%.0f days ago)", days)
-		log.Printf("To update, run: go get -u github.com/sentinelb51/revoltgo")
+		logger.Info("A new nightly update is available", "days_ago", int(days))
+		logger.Info("To update, run: go get -u github.com/sentinelb51/revoltgo")
 		return true
 	}
 
-	log.Printf("Update check complete; you are using the latest version of revoltgo")
+	logger.Info("Update check complete; you are using the latest version of revoltgo")
 	return false
 }
 
-/* Data structures for instance configuration, retrieved when you first contact apiURL */
+/* Data structures for instance configuration, retrieved when a session is opened; see InstanceURLs */
 
 type InstanceConfig struct {
 	WS       string                 `msg:"ws" json:"ws,omitempty"`
@@ -104,13 +107,40 @@ type InstanceConfigFeaturesVoso struct {
 	WS      string `msg:"ws" json:"ws,omitempty"`
 }
 
+// InstanceConfigFeaturesLiveKit is the voice service of instances calling through LiveKit.
+type InstanceConfigFeaturesLiveKit struct {
+	Enabled bool                                `msg:"enabled" json:"enabled,omitempty"`
+	Nodes   []InstanceConfigFeaturesLiveKitNode `msg:"nodes" json:"nodes,omitempty"`
+}
+
+// InstanceConfigFeaturesLiveKitNode is a LiveKit server calls can be placed on.
+type InstanceConfigFeaturesLiveKitNode struct {
+	Name      string  `msg:"name" json:"name,omitempty"`
+	Latitude  float64 `msg:"lat" json:"lat,omitempty"`
+	Longitude float64 `msg:"lon" json:"lon,omitempty"`
+	PublicURL string  `msg:"public_url" json:"public_url,omitempty"`
+}
+
 type InstanceConfigFeatures struct {
 	Captcha    InstanceConfigFeaturesCaptcha `msg:"captcha" json:"captcha,omitempty"`
 	Email      bool                          `msg:"email" json:"email,omitempty"`
 	InviteOnly bool                          `msg:"invite_only" json:"invite_only,omitempty"`
 	Autumn     InstanceConfigFeaturesAutumn  `msg:"autumn" json:"autumn,omitempty"`
 	January    InstanceConfigFeaturesJanuary `msg:"january" json:"january,omitempty"`
-	Voso       InstanceConfigFeaturesVoso    `msg:"voso" json:"voso,omitempty"`
+	LiveKit    InstanceConfigFeaturesLiveKit `msg:"livekit" json:"livekit,omitempty"`
+
+	// Voso is the legacy voice server, which LiveKit replaced
+	Voso InstanceConfigFeaturesVoso `msg:"voso" json:"voso,omitempty"`
+}
+
+// FileUploads reports whether the instance accepts uploads, i.e. runs Autumn.
+func (f InstanceConfigFeatures) FileUploads() bool {
+	return f.Autumn.Enabled
+}
+
+// Voice reports whether the instance has voice calls, i.e. runs LiveKit.
+func (f InstanceConfigFeatures) Voice() bool {
+	return f.LiveKit.Enabled
 }
 
 type InstanceConfigBuild struct {
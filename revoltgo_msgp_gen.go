@@ -6648,9 +6648,9 @@ func (z *EventMessageUnreact) Msgsize() (s int) {
 // MarshalMsg implements msgp.Marshaler
 func (z *EventMessageUpdate) MarshalMsg(b []byte) (o []byte, err error) {
 	o = msgp.Require(b, z.Msgsize())
-	// map header, size 4
+	// map header, size 5
 	// string "type"
-	o = append(o, 0x84, 0xa4, 0x74, 0x79, 0x70, 0x65)
+	o = append(o, 0x85, 0xa4, 0x74, 0x79, 0x70, 0x65)
 	o = msgp.AppendString(o, z.Type)
 	// string "id"
 	o = append(o, 0xa2, 0x69, 0x64)
@@ -6665,6 +6665,12 @@ func (z *EventMessageUpdate) MarshalMsg(b []byte) (o []byte, err error) {
 		err = msgp.WrapError(err, "Data")
 		return
 	}
+	// string "clear"
+	o = append(o, 0xa5, 0x63, 0x6c, 0x65, 0x61, 0x72)
+	o = msgp.AppendArrayHeader(o, uint32(len(z.Clear)))
+	for za0001 := range z.Clear {
+		o = msgp.AppendString(o, z.Clear[za0001])
+	}
 	return
 }
 
@@ -6710,6 +6716,25 @@ func (z *EventMessageUpdate) UnmarshalMsg(bts []byte) (o []byte, err error) {
 				err = msgp.WrapError(err, "Data")
 				return
 			}
+		case "clear":
+			var zb0002 uint32
+			zb0002, bts, err = msgp.ReadArrayHeaderBytes(bts)
+			if err != nil {
+				err = msgp.WrapError(err, "Clear")
+				return
+			}
+			if cap(z.Clear) >= int(zb0002) {
+				z.Clear = (z.Clear)[:zb0002]
+			} else {
+				z.Clear = make([]string, zb0002)
+			}
+			for za0001 := range z.Clear {
+				z.Clear[za0001], bts, err = msgp.ReadStringBytes(bts)
+				if err != nil {
+					err = msgp.WrapError(err, "Clear", za0001)
+					return
+				}
+			}
 		default:
 			bts, err = msgp.Skip(bts)
 			if err != nil {
@@ -6724,7 +6749,10 @@ func (z *EventMessageUpdate) UnmarshalMsg(bts []byte) (o []byte, err error) {
 
 // Msgsize returns an upper bound estimate of the number of bytes occupied by the serialized message
 func (z *EventMessageUpdate) Msgsize() (s int) {
-	s = 1 + 5 + msgp.StringPrefixSize + len(z.Type) + 3 + msgp.StringPrefixSize + len(z.ID) + 8 + msgp.StringPrefixSize + len(z.Channel) + 5 + z.Data.Msgsize()
+	s = 1 + 5 + msgp.StringPrefixSize + len(z.Type) + 3 + msgp.StringPrefixSize + len(z.ID) + 8 + msgp.StringPrefixSize + len(z.Channel) + 5 + z.Data.Msgsize() + 6 + msgp.ArrayHeaderSize
+	for za0001 := range z.Clear {
+		s += msgp.StringPrefixSize + len(z.Clear[za0001])
+	}
 	return
 }
 
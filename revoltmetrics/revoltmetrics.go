@@ -0,0 +1,239 @@
+/*
+Package revoltmetrics exports the measurements of a revoltgo.Session, without any dependencies.
+
+An Exporter implements revoltgo.Metrics. It can be published as an expvar variable,
+and serves the Prometheus text format over HTTP so it can be scraped directly:
+
+	exporter := revoltmetrics.New()
+	exporter.Publish("revoltgo") // visible at /debug/vars
+	http.Handle("/metrics", exporter)
+
+	session := revoltgo.New(token)
+	session.Metrics = exporter
+
+One Exporter may be shared by several sessions; their measurements are added together.
+*/
+package revoltmetrics
+
+import (
+	"cmp"
+	"expvar"
+	"fmt"
+	"io"
+	"maps"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sentinelb51/revoltgo"
+)
+
+var _ revoltgo.Metrics = (*Exporter)(nil)
+
+// summary accumulates observations of a duration.
+type summary struct {
+	Count   int64   `json:"count"`
+	Seconds float64 `json:"seconds"`
+}
+
+func (s *summary) observe(d time.Duration) {
+	s.Count++
+	s.Seconds += d.Seconds()
+}
+
+type requestKey struct {
+	method string
+	route  string
+	status int
+}
+
+type routeKey struct {
+	method string
+	route  string
+}
+
+// Exporter collects the measurements of one or more sessions. The zero value is not usable; use New.
+type Exporter struct {
+	mu sync.Mutex
+
+	requests       map[requestKey]*summary
+	ratelimitWaits map[routeKey]*summary
+	heartbeat      time.Duration
+	received       map[string]int64
+	dropped        map[string]int64
+	decodeFailures map[string]int64
+	reconnects     int64
+}
+
+// New returns an empty Exporter.
+func New() *Exporter {
+	return &Exporter{
+		requests:       make(map[requestKey]*summary),
+		ratelimitWaits: make(map[routeKey]*summary),
+		received:       make(map[string]int64),
+		dropped:        make(map[string]int64),
+		decodeFailures: make(map[string]int64),
+	}
+}
+
+func (e *Exporter) ObserveRequest(method, route string, status int, duration time.Duration) {
+	key := requestKey{method: method, route: route, status: status}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	observed := e.requests[key]
+	if observed == nil {
+		observed = new(summary)
+		e.requests[key] = observed
+	}
+
+	observed.observe(duration)
+}
+
+func (e *Exporter) ObserveRatelimitWait(method, route string, wait time.Duration) {
+	key := routeKey{method: method, route: route}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	observed := e.ratelimitWaits[key]
+	if observed == nil {
+		observed = new(summary)
+		e.ratelimitWaits[key] = observed
+	}
+
+	observed.observe(wait)
+}
+
+func (e *Exporter) ObserveHeartbeat(latency time.Duration) {
+	e.mu.Lock()
+	e.heartbeat = latency
+	e.mu.Unlock()
+}
+
+func (e *Exporter) IncEventReceived(eventType string) {
+	e.mu.Lock()
+	e.received[eventType]++
+	e.mu.Unlock()
+}
+
+func (e *Exporter) IncEventDropped(eventType string) {
+	e.mu.Lock()
+	e.dropped[eventType]++
+	e.mu.Unlock()
+}
+
+func (e *Exporter) IncDecodeFailure(eventType string) {
+	e.mu.Lock()
+	e.decodeFailures[eventType]++
+	e.mu.Unlock()
+}
+
+func (e *Exporter) IncReconnect() {
+	e.mu.Lock()
+	e.reconnects++
+	e.mu.Unlock()
+}
+
+// Publish makes the measurements visible through expvar under name, like expvar.Publish.
+// It panics if name is already in use.
+func (e *Exporter) Publish(name string) {
+	expvar.Publish(name, expvar.Func(e.snapshot))
+}
+
+// snapshot copies the measurements into JSON-friendly maps, for expvar.
+func (e *Exporter) snapshot() any {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	requests := make(map[string]summary, len(e.requests))
+	for key, observed := range e.requests {
+		requests[fmt.Sprintf("%s %s %d", key.method, key.route, key.status)] = *observed
+	}
+
+	ratelimitWaits := make(map[string]summary, len(e.ratelimitWaits))
+	for key, observed := range e.ratelimitWaits {
+		ratelimitWaits[key.method+" "+key.route] = *observed
+	}
+
+	return map[string]any{
+		"requests":          requests,
+		"ratelimit_waits":   ratelimitWaits,
+		"heartbeat_seconds": e.heartbeat.Seconds(),
+		"events_received":   maps.Clone(e.received),
+		"events_dropped":    maps.Clone(e.dropped),
+		"decode_failures":   maps.Clone(e.decodeFailures),
+		"reconnects":        e.reconnects,
+	}
+}
+
+// ServeHTTP writes the measurements in the Prometheus text exposition format.
+func (e *Exporter) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	_ = e.WritePrometheus(w)
+}
+
+// WritePrometheus writes the measurements in the Prometheus text exposition format.
+func (e *Exporter) WritePrometheus(w io.Writer) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	var b strings.Builder
+
+	header(&b, "revoltgo_http_request_duration_seconds", "summary", "Duration of every HTTP request attempt, excluding ratelimit waits.")
+	requests := slices.SortedFunc(maps.Keys(e.requests), func(a, b requestKey) int {
+		return cmp.Or(cmp.Compare(a.route, b.route), cmp.Compare(a.method, b.method), cmp.Compare(a.status, b.status))
+	})
+	for _, key := range requests {
+		labels := fmt.Sprintf(`method="%s",route="%s",status="%d"`, escape(key.method), escape(key.route), key.status)
+		writeSummary(&b, "revoltgo_http_request_duration_seconds", labels, e.requests[key])
+	}
+
+	header(&b, "revoltgo_ratelimit_wait_seconds", "summary", "Time HTTP requests waited for their ratelimit bucket.")
+	routes := slices.SortedFunc(maps.Keys(e.ratelimitWaits), func(a, b routeKey) int {
+		return cmp.Or(cmp.Compare(a.route, b.route), cmp.Compare(a.method, b.method))
+	})
+	for _, key := range routes {
+		labels := fmt.Sprintf(`method="%s",route="%s"`, escape(key.method), escape(key.route))
+		writeSummary(&b, "revoltgo_ratelimit_wait_seconds", labels, e.ratelimitWaits[key])
+	}
+
+	header(&b, "revoltgo_heartbeat_latency_seconds", "gauge", "Latency of the last acknowledged Websocket heartbeat.")
+	fmt.Fprintf(&b, "revoltgo_heartbeat_latency_seconds %g\n", e.heartbeat.Seconds())
+
+	writeCounters(&b, "revoltgo_events_received_total", "Events received from the Websocket.", e.received)
+	writeCounters(&b, "revoltgo_events_dropped_total", "Events received without any handler, or of an unknown type.", e.dropped)
+	writeCounters(&b, "revoltgo_event_decode_failures_total", "Events that could not be decoded.", e.decodeFailures)
+
+	header(&b, "revoltgo_reconnects_total", "counter", "Attempts to reconnect the Websocket.")
+	fmt.Fprintf(&b, "revoltgo_reconnects_total %d\n", e.reconnects)
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func header(b *strings.Builder, name, kind, help string) {
+	fmt.Fprintf(b, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, kind)
+}
+
+func writeSummary(b *strings.Builder, name, labels string, observed *summary) {
+	fmt.Fprintf(b, "%s_sum{%s} %g\n", name, labels, observed.Seconds)
+	fmt.Fprintf(b, "%s_count{%s} %d\n", name, labels, observed.Count)
+}
+
+func writeCounters(b *strings.Builder, name, help string, counters map[string]int64) {
+	header(b, name, "counter", help)
+	for _, eventType := range slices.Sorted(maps.Keys(counters)) {
+		fmt.Fprintf(b, "%s{type=\"%s\"} %d\n", name, escape(eventType), counters[eventType])
+	}
+}
+
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// escape escapes a Prometheus label value.
+func escape(value string) string {
+	return labelEscaper.Replace(value)
+}
@@ -0,0 +1,106 @@
+/*
+Package revoltotel traces a revoltgo.Session with OpenTelemetry.
+
+It is a separate module, so that revoltgo itself doesn't depend on OpenTelemetry:
+
+	session := revoltgo.New(token)
+	session.Tracer = revoltotel.New(nil) // uses otel.GetTracerProvider()
+
+REST calls become client spans, and event dispatches become consumer spans.
+
+It requires the first revoltgo release with Session.Tracer. To develop both modules together, use a workspace
+in the repository root, which is ignored by git and resolves the required release to the checkout:
+
+	go work init . ./revoltotel
+	go work edit -replace github.com/sentinelb51/revoltgo@v1.0.0=./
+*/
+package revoltotel
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/sentinelb51/revoltgo"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// InstrumentationName identifies the spans of this package.
+const InstrumentationName = "github.com/sentinelb51/revoltgo"
+
+var _ revoltgo.Tracer = (*Tracer)(nil)
+
+// Tracer adapts an OpenTelemetry trace.Tracer to revoltgo.Tracer.
+type Tracer struct {
+	tracer trace.Tracer
+}
+
+// New returns a Tracer creating spans from provider. If provider is nil, the global provider is used.
+func New(provider trace.TracerProvider) *Tracer {
+	if provider == nil {
+		provider = otel.GetTracerProvider()
+	}
+
+	return &Tracer{tracer: provider.Tracer(InstrumentationName)}
+}
+
+func (t *Tracer) Start(ctx context.Context, name string, attributes ...revoltgo.Attribute) (context.Context, revoltgo.Span) {
+	ctx, span := t.tracer.Start(ctx, name, trace.WithSpanKind(spanKind(name)), trace.WithAttributes(convert(attributes)...))
+	return ctx, Span{span: span}
+}
+
+// Span adapts an OpenTelemetry trace.Span to revoltgo.Span.
+type Span struct {
+	span trace.Span
+}
+
+func (s Span) SetAttributes(attributes ...revoltgo.Attribute) {
+	s.span.SetAttributes(convert(attributes)...)
+}
+
+func (s Span) RecordError(err error) {
+	s.span.RecordError(err)
+	s.span.SetStatus(codes.Error, err.Error())
+}
+
+func (s Span) End() {
+	s.span.End()
+}
+
+// spanKind derives the kind of span from the names revoltgo gives them.
+func spanKind(name string) trace.SpanKind {
+	switch {
+	case strings.HasPrefix(name, "revoltgo.request"):
+		return trace.SpanKindClient
+	case strings.HasPrefix(name, "revoltgo.event"):
+		return trace.SpanKindConsumer
+	}
+
+	return trace.SpanKindInternal
+}
+
+func convert(attributes []revoltgo.Attribute) []attribute.KeyValue {
+	converted := make([]attribute.KeyValue, len(attributes))
+
+	for i, a := range attributes {
+		switch value := a.Value.(type) {
+		case string:
+			converted[i] = attribute.String(a.Key, value)
+		case int:
+			converted[i] = attribute.Int(a.Key, value)
+		case int64:
+			converted[i] = attribute.Int64(a.Key, value)
+		case float64:
+			converted[i] = attribute.Float64(a.Key, value)
+		case bool:
+			converted[i] = attribute.Bool(a.Key, value)
+		default:
+			converted[i] = attribute.String(a.Key, fmt.Sprint(value))
+		}
+	}
+
+	return converted
+}
@@ -0,0 +1,135 @@
+package revolttest_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/sentinelb51/revoltgo"
+	"github.com/sentinelb51/revoltgo/revolttest"
+)
+
+// timeout bounds every wait of the tests.
+const timeout = 5 * time.Second
+
+// lockedBuffer is a bytes.Buffer that can be read while a session writes to it.
+type lockedBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *lockedBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *lockedBuffer) Bytes() []byte {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return bytes.Clone(b.buf.Bytes())
+}
+
+func TestRecorderKeepsOrder(t *testing.T) {
+	const events = 500
+
+	server := revolttest.NewServer()
+	defer server.Close()
+
+	var recording lockedBuffer
+
+	session := server.Session("token")
+	session.DispatchMode = revoltgo.DispatchParallel
+	session.Recorder = revoltgo.NewRecorder(&recording)
+
+	var handled atomic.Int64
+	done := make(chan struct{})
+	revoltgo.AddHandler(session, func(*revoltgo.Session, *revoltgo.EventChannelStartTyping) {
+		if handled.Add(1) == events {
+			close(done)
+		}
+	})
+
+	open(t, session)
+
+	for i := range events {
+		if err := server.Push(&revoltgo.EventChannelStartTyping{ID: strconv.Itoa(i), User: "user"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	wait(t, done, "the pushed events")
+
+	reader := revoltgo.NewRecordingReader(bytes.NewReader(recording.Bytes()))
+
+	next := 0
+	for {
+		frame, err := reader.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var event revoltgo.Event
+		if _, err = event.UnmarshalMsg(frame.Data); err != nil {
+			t.Fatal(err)
+		}
+
+		if event.Type != "ChannelStartTyping" {
+			continue
+		}
+
+		var typing revoltgo.EventChannelStartTyping
+		if _, err = typing.UnmarshalMsg(frame.Data); err != nil {
+			t.Fatal(err)
+		}
+
+		if typing.ID != strconv.Itoa(next) {
+			t.Fatalf("recorded event %d as event %s", next, typing.ID)
+		}
+
+		next++
+	}
+
+	if next != events {
+		t.Fatalf("recorded %d events, want %d", next, events)
+	}
+}
+
+// open opens session, and waits until it is ready.
+func open(t *testing.T, session *revoltgo.Session) {
+	t.Helper()
+
+	if err := session.Open(); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Cleanup(func() { _ = session.Close() })
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	if err := session.WaitUntilReady(ctx); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// wait waits for done to be closed, failing the test after a while.
+func wait(t *testing.T, done <-chan struct{}, what string) {
+	t.Helper()
+
+	select {
+	case <-done:
+	case <-time.After(timeout):
+		t.Fatalf("timed out waiting for %s", what)
+	}
+}
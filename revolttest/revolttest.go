@@ -0,0 +1,710 @@
+/*
+Package revolttest runs an in-process fake Revolt server, so bots can be tested end to end with go test,
+without a token or a network connection.
+
+A Server emulates the REST API over HTTPS, and a Websocket speaking the msgpack event protocol.
+It records every request and frame it receives, and events can be pushed to every connected session:
+
+	server := revolttest.NewServer()
+	defer server.Close()
+
+	session := server.Session("token")
+	revoltgo.AddHandler(session, onMessage)
+
+	if err := session.Open(); err != nil {
+		t.Fatal(err)
+	}
+
+	_ = session.WaitUntilReady(ctx)
+
+	_ = server.Push(&revoltgo.EventMessage{Message: revoltgo.Message{ID: "01J...", Channel: "general", Content: "!ping"}})
+
+Out of the box, the server answers the instance configuration, the users, channels and servers of its Ready event,
+and sending messages, which are pushed back as an EventMessage like the real server does. Other requests are
+answered with 404 Not Found, unless scripted with Handle.
+
+Like the real API, the server ratelimits requests by bucket, reports the limits in X-RateLimit-* headers, and answers
+requests over them with 429 Too Many Requests; see Server.Ratelimits.
+
+Sessions created with Server.Session send their requests to that Server only, so several Servers may be used
+at once, and tests using them may run in parallel.
+*/
+package revolttest
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/lxzan/gws"
+	"github.com/oklog/ulid/v2"
+	"github.com/sentinelb51/revoltgo"
+	"github.com/tinylib/msgp/msgp"
+)
+
+// Request is an HTTP request received by a Server.
+type Request struct {
+	Method string
+	Path   string
+	Query  url.Values
+	Header http.Header
+	Body   []byte
+}
+
+// Ratelimit is the limit of a bucket of the API: Limit requests every Window.
+type Ratelimit struct {
+	Limit  int
+	Window time.Duration
+}
+
+// DefaultRatelimits returns the limits of the real API, by bucket name; see Server.Ratelimits.
+func DefaultRatelimits() map[string]Ratelimit {
+	const window = 10 * time.Second
+
+	return map[string]Ratelimit{
+		"users":          {Limit: 20, Window: window},
+		"user_edit":      {Limit: 2, Window: window},
+		"default_avatar": {Limit: 255, Window: window},
+		"bots":           {Limit: 10, Window: window},
+		"messaging":      {Limit: 10, Window: window},
+		"channels":       {Limit: 15, Window: window},
+		"servers":        {Limit: 5, Window: window},
+		"auth":           {Limit: 15, Window: window},
+		"auth_delete":    {Limit: 255, Window: window},
+		"safety":         {Limit: 15, Window: window},
+		"any":            {Limit: 20, Window: window},
+	}
+}
+
+// Server is a fake Revolt instance. Configure its exported fields before opening any sessions against it.
+type Server struct {
+	// Token is the token sessions must authenticate with; empty accepts any token
+	Token string
+
+	// Self is the user the token belongs to. It is sent as the last user of the Ready event, answers
+	// GET /users/@me, and is the author of the messages sent through the server
+	Self *revoltgo.User
+
+	// Ready is sent to every session that connects, unless it is resuming. Self is appended to its users
+	Ready revoltgo.EventReady
+
+	// Instance answers the root of the API. Its Websocket and Autumn URLs are set by NewServer
+	Instance revoltgo.InstanceConfig
+
+	// Ratelimits are the limits of the API's buckets by name, DefaultRatelimits unless changed. Like the real API,
+	// messaging is limited per channel, editing a user per user, and servers per server. Requests to a bucket
+	// without a limit report no ratelimit headers; set Ratelimits to nil to test a client without them
+	Ratelimits map[string]Ratelimit
+
+	api      *httptest.Server
+	ws       *httptest.Server
+	upgrader *gws.Upgrader
+
+	builtin   *http.ServeMux
+	overrides *http.ServeMux
+
+	mu       sync.Mutex
+	requests []Request
+	frames   [][]byte
+	sockets  map[*gws.Conn]struct{}
+	buckets  map[string]*bucket
+	scripted bool
+}
+
+// bucket counts the requests left in the current window of a ratelimit bucket.
+type bucket struct {
+	remaining int
+	reset     time.Time
+}
+
+// NewServer starts a Server. Close it once done.
+func NewServer() *Server {
+	server := &Server{
+		Self: &revoltgo.User{
+			ID:       "01HZZZZZZZZZZZZZZZZZZZZZZZ",
+			Username: "revolttest",
+			Online:   true,
+			Bot:      &revoltgo.Bot{Owner: "01HYYYYYYYYYYYYYYYYYYYYYYY"},
+		},
+		Instance: revoltgo.InstanceConfig{
+			Revolt: "revolttest",
+		},
+		Ratelimits: DefaultRatelimits(),
+		builtin:    http.NewServeMux(),
+		overrides:  http.NewServeMux(),
+		sockets:    make(map[*gws.Conn]struct{}),
+		buckets:    make(map[string]*bucket),
+	}
+
+	server.builtin.HandleFunc("GET /{$}", server.instance)
+	server.builtin.HandleFunc("GET /users/{id}", server.user)
+	server.builtin.HandleFunc("GET /channels/{id}", server.channel)
+	server.builtin.HandleFunc("GET /servers/{id}", server.server)
+	server.builtin.HandleFunc("POST /channels/{id}/messages", server.sendMessage)
+	server.builtin.HandleFunc("/", notFound)
+
+	server.upgrader = gws.NewUpgrader(&socketHandler{server: server}, &gws.ServerOption{
+		CheckUtf8Enabled: false,
+	})
+
+	server.api = httptest.NewTLSServer(http.HandlerFunc(server.serveAPI))
+	server.ws = httptest.NewServer(http.HandlerFunc(server.serveWebsocket))
+	server.Instance.WS = "ws://" + server.ws.Listener.Addr().String()
+	server.Instance.Features.Autumn = revoltgo.InstanceConfigFeaturesAutumn{Enabled: true, URL: server.api.URL}
+
+	return server
+}
+
+// Session creates a session with token, whose API and CDN are the Server, and whose HTTP client trusts
+// the Server's certificate.
+func (s *Server) Session(token string) *revoltgo.Session {
+	session := revoltgo.New(token)
+	session.HTTP.SetClient(s.api.Client())
+
+	// httptest listens on 127.0.0.1, which passes the base URL validation
+	if err := session.HTTP.SetBaseURL(s.api.URL); err != nil {
+		panic(fmt.Sprintf("revolttest: %v", err))
+	}
+
+	if err := session.HTTP.SetCDNURL(s.api.URL); err != nil {
+		panic(fmt.Sprintf("revolttest: %v", err))
+	}
+
+	return session
+}
+
+// URL is the base URL of the Server's REST API.
+func (s *Server) URL() string {
+	return s.api.URL
+}
+
+// Close disconnects every session and shuts the Server down.
+func (s *Server) Close() {
+	s.CloseSockets(1001)
+	s.ws.Close()
+	s.api.Close()
+}
+
+// Handle scripts the response to requests matching pattern, a http.ServeMux pattern such as
+// "DELETE /channels/{id}/messages/{message}". Scripted handlers take precedence over the built-in ones.
+func (s *Server) Handle(pattern string, handler http.HandlerFunc) {
+	s.mu.Lock()
+	s.scripted = true
+	s.mu.Unlock()
+
+	s.overrides.HandleFunc(pattern, handler)
+}
+
+// JSON returns a handler that responds with status and body encoded as JSON, for use with Handle.
+func JSON(status int, body any) http.HandlerFunc {
+	return func(w http.ResponseWriter, _ *http.Request) {
+		writeJSON(w, status, body)
+	}
+}
+
+// Requests returns the HTTP requests received so far, oldest first.
+func (s *Server) Requests() []Request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]Request(nil), s.requests...)
+}
+
+// Frames returns the Websocket frames received from sessions so far, oldest first.
+func (s *Server) Frames() [][]byte {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([][]byte(nil), s.frames...)
+}
+
+// Push sends an event to every connected session. The event type is taken from the event,
+// or else derived from its Go type, so &revoltgo.EventMessage{...} is sent as a "Message" event.
+// Like the real server, it leaves out empty fields, so only the fields set are applied by an update event.
+func (s *Server) Push(event msgp.Marshaler) error {
+	frame, err := encodeEvent(event)
+	if err != nil {
+		return err
+	}
+
+	return s.PushRaw(frame)
+}
+
+// PushRaw sends a msgpack frame as is to every connected session.
+func (s *Server) PushRaw(frame []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for socket := range s.sockets {
+		if err := socket.WriteMessage(gws.OpcodeBinary, frame); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// CloseSockets closes every Websocket connection with code, e.g. to test reconnection.
+func (s *Server) CloseSockets(code uint16) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for socket := range s.sockets {
+		_ = socket.WriteClose(code, nil)
+	}
+}
+
+func (s *Server) serveAPI(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+
+	s.mu.Lock()
+	s.requests = append(s.requests, Request{
+		Method: r.Method,
+		Path:   r.URL.Path,
+		Query:  r.URL.Query(),
+		Header: r.Header.Clone(),
+		Body:   body,
+	})
+	scripted := s.scripted
+	s.mu.Unlock()
+
+	r.Body = io.NopCloser(strings.NewReader(string(body)))
+
+	if !s.ratelimit(w, r) {
+		return
+	}
+
+	if scripted {
+		if handler, pattern := s.overrides.Handler(r); pattern != "" {
+			handler.ServeHTTP(w, r)
+			return
+		}
+	}
+
+	s.builtin.ServeHTTP(w, r)
+}
+
+// ratelimit counts the request against its bucket, and reports the bucket in the response's headers.
+// It answers a request over the limit with 429 Too Many Requests, and returns false.
+func (s *Server) ratelimit(w http.ResponseWriter, r *http.Request) bool {
+	name, resource := ratelimitBucket(r.Method, r.URL.Path)
+
+	limit := s.Ratelimits[name]
+	if limit.Limit <= 0 {
+		return true
+	}
+
+	key := name + ":" + resource
+	now := time.Now()
+
+	s.mu.Lock()
+	b := s.buckets[key]
+	if b == nil || !now.Before(b.reset) {
+		b = &bucket{remaining: limit.Limit, reset: now.Add(limit.Window)}
+		s.buckets[key] = b
+	}
+
+	allowed := b.remaining > 0
+	if allowed {
+		b.remaining--
+	}
+
+	remaining := b.remaining
+	resetAfter := b.reset.Sub(now).Milliseconds() + 1 // Rounded up, so waiting it out always refills the bucket
+	s.mu.Unlock()
+
+	header := w.Header()
+	header.Set("X-RateLimit-Bucket", name)
+	header.Set("X-RateLimit-Limit", strconv.Itoa(limit.Limit))
+	header.Set("X-RateLimit-Remaining", strconv.Itoa(remaining))
+	header.Set("X-RateLimit-Reset-After", strconv.FormatInt(resetAfter, 10))
+
+	if !allowed {
+		writeJSON(w, http.StatusTooManyRequests, map[string]int64{"retry_after": resetAfter})
+	}
+
+	return allowed
+}
+
+// ratelimitBucket returns the name of the bucket a request is counted against, and the resource (a channel,
+// server or user ID) the bucket is scoped to, if any.
+func ratelimitBucket(method, path string) (name, resource string) {
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+
+	switch segments[0] {
+	case "users":
+		if method == http.MethodPatch && len(segments) == 2 {
+			return "user_edit", segments[1]
+		}
+
+		if len(segments) == 3 && segments[2] == "default_avatar" {
+			return "default_avatar", ""
+		}
+
+		return "users", ""
+	case "bots":
+		return "bots", ""
+	case "channels":
+		if method == http.MethodPost && len(segments) == 3 && segments[2] == "messages" {
+			return "messaging", segments[1]
+		}
+
+		return "channels", ""
+	case "servers":
+		if len(segments) > 1 {
+			return "servers", segments[1]
+		}
+
+		return "servers", ""
+	case "auth":
+		if method == http.MethodDelete {
+			return "auth_delete", ""
+		}
+
+		return "auth", ""
+	case "safety":
+		return "safety", ""
+	}
+
+	return "any", ""
+}
+
+func (s *Server) instance(w http.ResponseWriter, _ *http.Request) {
+	writeJSON(w, http.StatusOK, s.Instance)
+}
+
+func (s *Server) user(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	if id == "@me" || id == s.Self.ID {
+		writeJSON(w, http.StatusOK, s.Self)
+		return
+	}
+
+	for _, user := range s.Ready.Users {
+		if user.ID == id {
+			writeJSON(w, http.StatusOK, user)
+			return
+		}
+	}
+
+	notFound(w, r)
+}
+
+func (s *Server) channel(w http.ResponseWriter, r *http.Request) {
+	for _, channel := range s.Ready.Channels {
+		if channel.ID == r.PathValue("id") {
+			writeJSON(w, http.StatusOK, channel)
+			return
+		}
+	}
+
+	notFound(w, r)
+}
+
+func (s *Server) server(w http.ResponseWriter, r *http.Request) {
+	for _, server := range s.Ready.Servers {
+		if server.ID == r.PathValue("id") {
+			writeJSON(w, http.StatusOK, server)
+			return
+		}
+	}
+
+	notFound(w, r)
+}
+
+// sendMessage creates the message, and pushes it to every session like the real server does.
+func (s *Server) sendMessage(w http.ResponseWriter, r *http.Request) {
+	var data revoltgo.MessageSend
+	if err := json.NewDecoder(r.Body).Decode(&data); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"type": revoltgo.APIErrorTypeFailedValidation})
+		return
+	}
+
+	message := revoltgo.Message{
+		ID:         ulid.Make().String(),
+		Author:     s.Self.ID,
+		Channel:    r.PathValue("id"),
+		Content:    data.Content,
+		Embeds:     data.Embeds,
+		Masquerade: data.Masquerade,
+	}
+
+	for _, reply := range data.Replies {
+		message.Replies = append(message.Replies, reply.ID)
+	}
+
+	writeJSON(w, http.StatusOK, message)
+	_ = s.Push(&revoltgo.EventMessage{Event: revoltgo.Event{Type: "Message"}, Message: message})
+}
+
+func notFound(w http.ResponseWriter, _ *http.Request) {
+	writeJSON(w, http.StatusNotFound, map[string]string{"type": revoltgo.APIErrorTypeNotFound})
+}
+
+func writeJSON(w http.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
+
+// serveWebsocket authenticates a session from its query parameters, like the real server.
+func (s *Server) serveWebsocket(w http.ResponseWriter, r *http.Request) {
+	socket, err := s.upgrader.Upgrade(w, r)
+	if err != nil {
+		return
+	}
+
+	query := r.URL.Query()
+	if s.Token != "" && query.Get("token") != s.Token {
+		frame, _ := encodeEvent(&revoltgo.EventError{
+			Event: revoltgo.Event{Type: "Error"},
+			Data:  revoltgo.EventErrorData{Type: revoltgo.EventErrorInvalidSession},
+		})
+		_ = socket.WriteMessage(gws.OpcodeBinary, frame)
+		go socket.ReadLoop()
+		return
+	}
+
+	// Registered under the lock Push takes, so that events pushed once the session is ready reach it,
+	// and never before its Ready
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	frame, _ := encodeEvent(&revoltgo.EventAuthenticated{Event: revoltgo.Event{Type: "Authenticated"}})
+	_ = socket.WriteMessage(gws.OpcodeBinary, frame)
+
+	if query.Get("reconnect") != "true" {
+		ready := s.Ready
+		ready.Type = "Ready"
+		ready.Users = append(append([]*revoltgo.User(nil), ready.Users...), s.Self)
+
+		frame, err = encodeEvent(&ready)
+		if err != nil {
+			panic(fmt.Sprintf("revolttest: encoding Ready: %v", err))
+		}
+
+		_ = socket.WriteMessage(gws.OpcodeBinary, frame)
+	}
+
+	s.sockets[socket] = struct{}{}
+
+	go socket.ReadLoop()
+}
+
+// socketHandler handles the gws events of the Server's connections.
+type socketHandler struct {
+	gws.BuiltinEventHandler
+	server *Server
+}
+
+func (h *socketHandler) OnClose(socket *gws.Conn, _ error) {
+	h.server.mu.Lock()
+	delete(h.server.sockets, socket)
+	h.server.mu.Unlock()
+}
+
+// OnPing answers heartbeats, echoing their payload like the real server.
+func (h *socketHandler) OnPing(socket *gws.Conn, payload []byte) {
+	_ = socket.WritePong(payload)
+}
+
+func (h *socketHandler) OnMessage(_ *gws.Conn, message *gws.Message) {
+	frame := append([]byte(nil), message.Data.Bytes()...)
+	_ = message.Close()
+
+	h.server.mu.Lock()
+	h.server.frames = append(h.server.frames, frame)
+	h.server.mu.Unlock()
+}
+
+// maxFixmap is the most fields a frame can have; the library expects the "type" key right after a fixmap header.
+const maxFixmap = 15
+
+// encodeEvent encodes an event like the real server: "type" first, without the library's nested Event,
+// and without empty fields.
+func encodeEvent(event msgp.Marshaler) ([]byte, error) {
+	data, err := event.MarshalMsg(nil)
+	if err != nil {
+		return nil, err
+	}
+
+	size, rest, err := msgp.ReadMapHeaderBytes(data)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		eventType string
+		fields    []byte
+		count     uint32
+	)
+
+	for range size {
+		var key, value []byte
+
+		key, rest, err = msgp.ReadMapKeyZC(rest)
+		if err != nil {
+			return nil, err
+		}
+
+		value = rest
+		if rest, err = msgp.Skip(rest); err != nil {
+			return nil, err
+		}
+		value = value[:len(value)-len(rest)]
+
+		switch string(key) {
+		case "type":
+			eventType, _, _ = msgp.ReadStringBytes(value)
+		case "Event":
+			eventType = nestedType(value)
+		default:
+			if value, err = compact(value); err != nil {
+				return nil, err
+			}
+
+			if empty(value) {
+				continue
+			}
+
+			fields = msgp.AppendStringFromBytes(fields, key)
+			fields = append(fields, value...)
+			count++
+		}
+	}
+
+	if eventType == "" {
+		eventType = strings.TrimPrefix(typeName(event), "Event")
+	}
+
+	if count+1 > maxFixmap {
+		return nil, fmt.Errorf("revolttest: %s event has %d non-empty fields, at most %d fit in a frame",
+			eventType, count, maxFixmap-1)
+	}
+
+	frame := msgp.AppendMapHeader(make([]byte, 0, len(fields)+len(eventType)+8), count+1)
+	frame = msgp.AppendString(frame, "type")
+	frame = msgp.AppendString(frame, eventType)
+	return append(frame, fields...), nil
+}
+
+// nestedType reads the type of an Event encoded as its own map.
+func nestedType(value []byte) string {
+	size, rest, err := msgp.ReadMapHeaderBytes(value)
+	if err != nil {
+		return ""
+	}
+
+	for range size {
+		var key []byte
+		if key, rest, err = msgp.ReadMapKeyZC(rest); err != nil {
+			return ""
+		}
+
+		if string(key) == "type" {
+			eventType, _, _ := msgp.ReadStringBytes(rest)
+			return eventType
+		}
+
+		if rest, err = msgp.Skip(rest); err != nil {
+			return ""
+		}
+	}
+
+	return ""
+}
+
+// compact drops the empty fields of an encoded map and of the maps nested in it, as the real server omits them;
+// an empty field of an update's data would otherwise clear the cached one. Other values are returned as they are.
+func compact(value []byte) ([]byte, error) {
+	switch msgp.NextType(value) {
+	case msgp.MapType:
+		size, rest, err := msgp.ReadMapHeaderBytes(value)
+		if err != nil {
+			return nil, err
+		}
+
+		var (
+			fields []byte
+			count  uint32
+		)
+
+		for range size {
+			var key, field []byte
+
+			key = rest
+			if rest, err = msgp.Skip(rest); err != nil {
+				return nil, err
+			}
+			key = key[:len(key)-len(rest)]
+
+			field = rest
+			if rest, err = msgp.Skip(rest); err != nil {
+				return nil, err
+			}
+
+			if field, err = compact(field[:len(field)-len(rest)]); err != nil {
+				return nil, err
+			}
+
+			if empty(field) {
+				continue
+			}
+
+			fields = append(append(fields, key...), field...)
+			count++
+		}
+
+		return append(msgp.AppendMapHeader(nil, count), fields...), nil
+	case msgp.ArrayType:
+		size, rest, err := msgp.ReadArrayHeaderBytes(value)
+		if err != nil {
+			return nil, err
+		}
+
+		array := msgp.AppendArrayHeader(nil, size)
+		for range size {
+			element := rest
+			if rest, err = msgp.Skip(rest); err != nil {
+				return nil, err
+			}
+
+			if element, err = compact(element[:len(element)-len(rest)]); err != nil {
+				return nil, err
+			}
+
+			array = append(array, element...)
+		}
+
+		return array, nil
+	}
+
+	return value, nil
+}
+
+// empty reports whether an encoded value is nil, false, or an empty string, array or map.
+func empty(value []byte) bool {
+	switch value[0] {
+	case 0xc0, 0xc2, 0xa0, 0x90, 0x80:
+		return true
+	}
+
+	return false
+}
+
+func typeName(event any) string {
+	t := reflect.TypeOf(event)
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	return t.Name()
+}
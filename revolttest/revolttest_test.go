@@ -0,0 +1,198 @@
+package revolttest_test
+
+import (
+	"errors"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/sentinelb51/revoltgo"
+	"github.com/sentinelb51/revoltgo/revolttest"
+)
+
+func TestOpenReady(t *testing.T) {
+	server := revolttest.NewServer()
+	defer server.Close()
+
+	session := server.Session("token")
+	open(t, session)
+
+	if state := session.WS.ConnectionState(); state != revoltgo.ConnectionStateReady {
+		t.Fatalf("connection state is %v, want ready", state)
+	}
+
+	self := session.State.Self()
+	if self == nil || self.ID != server.Self.ID {
+		t.Fatalf("self is %+v, want %s", self, server.Self.ID)
+	}
+
+	if session.Selfbot() {
+		t.Fatal("bot token detected as a selfbot")
+	}
+}
+
+func TestHandlerReceivesPushedEvent(t *testing.T) {
+	server := revolttest.NewServer()
+	defer server.Close()
+
+	session := server.Session("token")
+
+	received := make(chan *revoltgo.EventMessage, 1)
+	revoltgo.AddHandler(session, func(_ *revoltgo.Session, e *revoltgo.EventMessage) {
+		received <- e
+	})
+
+	open(t, session)
+
+	err := server.Push(&revoltgo.EventMessage{Message: revoltgo.Message{
+		ID:      "01J00000000000000000000000",
+		Channel: "general",
+		Author:  "01J00000000000000000000001",
+		Content: "!ping",
+	}})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case e := <-received:
+		if e.Content != "!ping" || e.Channel != "general" {
+			t.Fatalf("received %+v", e.Message)
+		}
+	case <-time.After(timeout):
+		t.Fatal("timed out waiting for the message")
+	}
+}
+
+func TestStateFromReady(t *testing.T) {
+	server := revolttest.NewServer()
+	defer server.Close()
+
+	server.Ready.Servers = []*revoltgo.Server{{
+		ID:       "01J00000000000000000000002",
+		Owner:    server.Self.ID,
+		Name:     "Test server",
+		Channels: []string{"01J00000000000000000000003"},
+	}}
+
+	server.Ready.Channels = []*revoltgo.Channel{{
+		ID:          "01J00000000000000000000003",
+		ChannelType: revoltgo.ChannelTypeText,
+		Name:        "general",
+		Server:      new("01J00000000000000000000002"),
+	}}
+
+	session := server.Session("token")
+	open(t, session)
+
+	if s := session.State.Server("01J00000000000000000000002"); s == nil || s.Name != "Test server" {
+		t.Fatalf("server is %+v", s)
+	}
+
+	if c := session.State.Channel("01J00000000000000000000003"); c == nil || c.Name != "general" {
+		t.Fatalf("channel is %+v", c)
+	}
+}
+
+func TestTypedErrors(t *testing.T) {
+	server := revolttest.NewServer()
+	defer server.Close()
+
+	server.Handle("POST /channels/{id}/messages", revolttest.JSON(http.StatusForbidden, map[string]string{
+		"type":       revoltgo.APIErrorTypeMissingPermission,
+		"permission": "SendMessage",
+	}))
+
+	session := server.Session("token")
+
+	_, err := session.ChannelMessage("general", "01J00000000000000000000000")
+	if !errors.Is(err, revoltgo.ErrNotFound) {
+		t.Fatalf("fetching an unknown message: %v, want ErrNotFound", err)
+	}
+
+	_, err = session.ChannelMessageSend("general", revoltgo.MessageSend{Content: "hello"})
+	if !errors.Is(err, revoltgo.ErrMissingPermission) {
+		t.Fatalf("sending without permission: %v, want ErrMissingPermission", err)
+	}
+
+	var apiErr *revoltgo.APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("%T is not an *APIError", err)
+	}
+
+	if apiErr.StatusCode != http.StatusForbidden || apiErr.Permission != "SendMessage" ||
+		apiErr.Route != revoltgo.URLChannelMessages {
+		t.Fatalf("error is %+v", apiErr)
+	}
+}
+
+func TestReconnectResumes(t *testing.T) {
+	server := revolttest.NewServer()
+	defer server.Close()
+
+	session := server.Session("token")
+
+	resumed := make(chan struct{}, 1)
+	revoltgo.AddHandler(session, func(*revoltgo.Session, *revoltgo.EventResumed) {
+		resumed <- struct{}{}
+	})
+
+	var readies atomic.Int32
+	revoltgo.AddHandler(session, func(*revoltgo.Session, *revoltgo.EventReady) {
+		readies.Add(1)
+	})
+
+	received := make(chan struct{}, 1)
+	revoltgo.AddHandler(session, func(*revoltgo.Session, *revoltgo.EventMessage) {
+		received <- struct{}{}
+	})
+
+	open(t, session)
+	session.WS.Resume = true
+
+	server.CloseSockets(1001) // Going away, like a server restart
+	wait(t, resumed, "the session to resume")
+
+	if n := readies.Load(); n != 1 {
+		t.Fatalf("received %d Ready events, want 1", n)
+	}
+
+	if session.State.Self() == nil {
+		t.Fatal("resuming lost the State")
+	}
+
+	if err := server.Push(&revoltgo.EventMessage{Message: revoltgo.Message{ID: "01J00000000000000000000000"}}); err != nil {
+		t.Fatal(err)
+	}
+
+	wait(t, received, "a message on the new connection")
+}
+
+func TestReconnectRebuildsState(t *testing.T) {
+	server := revolttest.NewServer()
+	defer server.Close()
+
+	session := server.Session("token")
+
+	var resumes atomic.Int32
+	revoltgo.AddHandler(session, func(*revoltgo.Session, *revoltgo.EventResumed) {
+		resumes.Add(1)
+	})
+
+	readies := make(chan struct{}, 2)
+	revoltgo.AddHandler(session, func(*revoltgo.Session, *revoltgo.EventReady) {
+		readies <- struct{}{}
+	})
+
+	open(t, session)
+	wait(t, readies, "the first Ready")
+
+	server.CloseSockets(1001)
+	wait(t, readies, "a new Ready after reconnecting")
+
+	if n := resumes.Load(); n != 0 {
+		t.Fatalf("resumed %d times without Websocket.Resume", n)
+	}
+}
@@ -86,7 +86,7 @@ func (s *Server) update(data PartialServer) {
 	}
 }
 
-func (s *Server) clear(fields []string) {
+func (s *Server) clear(fields []string) (unknown []string) {
 	for _, field := range fields {
 		switch field {
 		case "Icon":
@@ -96,9 +96,11 @@ func (s *Server) clear(fields []string) {
 		case "Description":
 			s.Description = ""
 		default:
-			fmt.Printf("Server.clear(): unknown field %s\n", field)
+			unknown = append(unknown, field)
 		}
 	}
+
+	return unknown
 }
 
 // PartialServer is only found within EventServerUpdate and used to update the state.
@@ -153,15 +155,17 @@ func (r *ServerRole) update(data PartialServerRole) {
 	}
 }
 
-func (r *ServerRole) clear(fields []string) {
+func (r *ServerRole) clear(fields []string) (unknown []string) {
 	for _, field := range fields {
 		switch field {
 		case "Colour":
 			r.Colour = nil
 		default:
-			fmt.Printf("ServerRole.clear(): unknown field %s\n", field)
+			unknown = append(unknown, field)
 		}
 	}
+
+	return unknown
 }
 
 type PartialServerRole struct {
@@ -230,7 +234,7 @@ func (m *ServerMember) update(data PartialServerMember) {
 }
 
 // Clear resets nullable fields to nil based on the JSON key name.
-func (m *ServerMember) clear(fields []string) {
+func (m *ServerMember) clear(fields []string) (unknown []string) {
 	for _, field := range fields {
 		switch field {
 		case string(WebhookRemoveNickname):
@@ -238,9 +242,11 @@ func (m *ServerMember) clear(fields []string) {
 		case string(WebhookRemoveAvatar):
 			m.Avatar = nil
 		default:
-			fmt.Printf("ServerMember.clear(): unknown field %s\n", field)
+			unknown = append(unknown, field)
 		}
 	}
+
+	return unknown
 }
 
 type PartialServerMember struct {
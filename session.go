@@ -1,12 +1,15 @@
 package revoltgo
 
 import (
+	"context"
+	"errors"
 	"fmt"
-	"log"
+	"log/slog"
 	"net/http"
 	"net/url"
 	"os"
 	"reflect"
+	"slices"
 	"strings"
 	"sync"
 	"sync/atomic"
@@ -26,9 +29,11 @@ func New(token string) *Session {
 		State: newState(),
 	}
 
+	session.State.logger = session.logger
+
 	session.handlers.Store(&sessionHandlers{
 		defaults: make(map[string]func(*Session, any)),
-		user:     make(map[string][]func(*Session, any)),
+		user:     make(map[string][]*userHandler),
 	})
 
 	session.HTTP = newHTTPClient(session)
@@ -42,7 +47,7 @@ func New(token string) *Session {
 // You are expected to store and re-use the token for future sessions.
 func NewWithLogin(data LoginParams) (*Session, LoginResponse, error) {
 	session := New("")
-	session.selfbot = true
+	session.selfbot.Store(true)
 
 	if data.FriendlyName == "" {
 		data.FriendlyName = fmt.Sprintf("RevoltGo/%s (%d)", VERSION, os.Getpid())
@@ -76,21 +81,20 @@ func NewWithExpressLogin(data LoginParams) (*Session, error) {
 
 	// If token exists, use it
 	if token != "" {
-		log.Println("Attempting to re-use existing token")
 		session := New(token)
-		session.selfbot = true
+		session.selfbot.Store(true)
+		session.logger().Info("Re-using existing token", "file", ExpressLoginFile)
 		return session, nil
 	}
 
 	// Otherwise, perform login
-	log.Println("Performing authentication...")
 	session, mfa, err := NewWithLogin(data)
 	if err != nil {
 		return nil, err
 	}
 
 	// Save token to file
-	log.Println("Saving authentication token for re-use")
+	session.logger().Info("Saving authentication token for re-use", "file", ExpressLoginFile)
 	err = os.WriteFile(ExpressLoginFile, []byte(mfa.Token), 0o600)
 	return session, err
 }
@@ -103,20 +107,52 @@ type Session struct {
 	State           *State      // State is a central store for all data received from the API
 	CheckForUpdates bool        // Whether to check for updates in the default EventReady handler
 
+	// Logger receives the library's logs, with fields such as "event", "endpoint" and "code".
+	// If nil, slog.Default() is used; the library never changes global log settings.
+	Logger *slog.Logger
+
+	// Metrics receives measurements of HTTP requests, ratelimits and the Websocket. If nil, nothing is measured
+	Metrics Metrics
+
+	// Tracer starts spans around REST calls and event handlers. If nil, nothing is traced
+	Tracer Tracer
+
+	// Recorder records every frame received from the Websocket, to be replayed with Replay. If nil, nothing is recorded
+	Recorder *Recorder
+
+	// DispatchMode decides where user handlers run, and in which order; DispatchParallel by default.
+	// DispatchWorkers is the number of workers of DispatchWorkerPool; runtime.NumCPU() if zero.
+	// Both are read when the session is opened
+	DispatchMode    DispatchMode
+	DispatchWorkers int
+
 	// todo: maybe selfbot can be derived from runtime? maybe call User(@me) before connect
-	selfbot bool // Whether the session is a user or bot
+	selfbot atomic.Bool // Whether the session is a user or bot
+
+	instance atomic.Pointer[InstanceConfig] // Cached by FetchInstance
 
 	// handlersMu only serialises writers against each other. The hot path reads
 	// handlers lock-free via Load().
 	handlersMu sync.Mutex
 	handlers   atomic.Pointer[sessionHandlers]
+	handlerIDs atomic.Uint64 // Source of userHandler.id
 }
 
 type sessionHandlers struct {
 	// Library's own handlers that maintain the state and ensure user handlers get up-to-date information
 	defaults map[string]func(*Session, any)
 	// User-defined handlers, dispatched after defaults are done
-	user map[string][]func(*Session, any)
+	user map[string][]*userHandler
+	// Wraps every user handler, outermost first; see Session.Use
+	middleware []Middleware
+}
+
+// userHandler is a registered user handler. Funcs aren't comparable, so it is identified by id to be removed;
+// the id outlives the userHandler itself, which Session.Use replaces to wrap it in new middleware.
+type userHandler struct {
+	id      uint64
+	handler HandlerFunc // As registered
+	call    HandlerFunc // handler wrapped in the middleware
 }
 
 // clone copies the maps but shares their values with the original. Don't edit a
@@ -125,7 +161,9 @@ type sessionHandlers struct {
 func (h *sessionHandlers) clone() *sessionHandlers {
 	next := &sessionHandlers{
 		defaults: make(map[string]func(*Session, any), len(h.defaults)),
-		user:     make(map[string][]func(*Session, any), len(h.user)),
+		user:     make(map[string][]*userHandler, len(h.user)),
+
+		middleware: h.middleware,
 	}
 
 	for name, handler := range h.defaults {
@@ -139,9 +177,27 @@ func (h *sessionHandlers) clone() *sessionHandlers {
 	return next
 }
 
+// logger returns the Session's Logger, falling back to slog.Default()
+func (s *Session) logger() *slog.Logger {
+	if s.Logger != nil {
+		return s.Logger
+	}
+
+	return slog.Default()
+}
+
+// metrics returns the Session's Metrics, falling back to NoopMetrics
+func (s *Session) metrics() Metrics {
+	if s.Metrics != nil {
+		return s.Metrics
+	}
+
+	return NoopMetrics{}
+}
+
 // Selfbot returns whether the session is a selfbot
 func (s *Session) Selfbot() bool {
-	return s.selfbot
+	return s.selfbot.Load()
 }
 
 // addDefaultHandlers registers the library's own handlers. It is called from Open once tracking is known:
@@ -163,24 +219,36 @@ func (s *Session) addDefaultHandlers() {
 
 		// todo: future logic should maybe invalidate ExpressLoginFile file if present
 
+		logger := s.logger().With("error", e.Data.Type)
+
 		switch e.Data.Type {
 		case EventErrorAlreadyAuthenticated:
-			log.Println("Attempted authentication when already authenticated")
+			logger.Warn("Attempted authentication when already authenticated")
 		case EventErrorInvalidSession:
-			log.Println("Invalid session token; it is either malformed or revoked")
+			logger.Error("Invalid session token; it is either malformed or revoked")
 		case EventErrorOnboardingNotFinished:
-			log.Println("Onboarding not finished; please complete onboarding in the official client")
+			logger.Error("Onboarding not finished; please complete onboarding in the official client")
 		case EventErrorLabelMe:
-			log.Println("Unlabeled error; please report this to the Revolt's developers")
+			logger.Error("Unlabeled error; please report this to the Revolt's developers")
 		case EventErrorInternalError:
-			log.Println("Internal server error; please try again later")
+			logger.Error("Internal server error; please try again later")
 		}
 
-		_ = s.Close()
+		if s.WS.ReconnectPolicy.fatalError(e.Data.Type) {
+			s.WS.giveUp(fmt.Errorf("reconnect: fatal websocket error %s", e.Data.Type))
+			return
+		}
+
+		// Failures to authenticate are worth another try; dropping the connection makes the Websocket reconnect.
+		// Anything else (e.g. AlreadyAuthenticated) is only a warning, and the connection is still good
+		switch e.Data.Type {
+		case EventErrorInternalError, EventErrorLabelMe:
+			s.WS.drop()
+		}
 	})
 
 	addDefaultHandler(s, func(s *Session, e *EventLogout) {
-		log.Println("Logout event received; closing session")
+		s.logger().Info("Logout event received; closing session")
 		_ = s.Close()
 	})
 
@@ -191,24 +259,38 @@ func (s *Session) addDefaultHandlers() {
 		}
 	})
 
+	addDefaultHandler(s, func(s *Session, e *EventAuthenticated) {
+		s.WS.resumed()
+	})
+
 	addDefaultHandler(s, func(s *Session, e *EventReady) {
-		s.State.populate(e)
+
+		// The server may still send a ready event on a resumed connection; merge it rather than rebuild the State
+		if s.WS.resuming.Load() {
+			s.State.reconcile(e)
+		} else {
+			s.State.populate(e)
+		}
 
 		if s.State.Self() == nil {
 			self, err := s.User("@me")
 			if err == nil {
-				log.Printf("Identified self via API call\n")
+				s.logger().Debug("Identified self via API call")
 				s.State.setSelf(self)
 			} else {
-				log.Printf("Failed to identify self: %s\n", err)
+				s.logger().Warn("Failed to identify self", "err", err)
 			}
 		}
 
 		self := s.State.Self()
-		s.selfbot = self != nil && self.Bot == nil
+		s.selfbot.Store(self != nil && self.Bot == nil)
+
+		// Only now, so that everything above is settled once WaitUntilReady returns
+		s.WS.ready.Store(true)
+		s.WS.setState(ConnectionStateReady)
 
 		if s.CheckForUpdates {
-			go HasUpdate()
+			go hasUpdate(s.logger())
 		}
 	})
 
@@ -218,7 +300,8 @@ func (s *Session) addDefaultHandlers() {
 		})
 
 		addDefaultHandler(s, func(s *Session, e *EventUserUpdate) {
-			s.State.updateUser(e)
+			before, after := s.State.updateUser(e)
+			s.dispatch("UserUpdateSnapshot", &EventUserUpdateSnapshot{EventUserUpdate: e, Before: before, After: after})
 		})
 	}
 
@@ -228,11 +311,13 @@ func (s *Session) addDefaultHandlers() {
 		})
 
 		addDefaultHandler(s, func(s *Session, e *EventChannelDelete) {
-			s.State.deleteChannel(e)
+			before := s.State.deleteChannel(e)
+			s.dispatch("ChannelDeleteSnapshot", &EventChannelDeleteSnapshot{EventChannelDelete: e, Before: before})
 		})
 
 		addDefaultHandler(s, func(s *Session, e *EventChannelUpdate) {
-			s.State.updateChannel(e)
+			before, after := s.State.updateChannel(e)
+			s.dispatch("ChannelUpdateSnapshot", &EventChannelUpdateSnapshot{EventChannelUpdate: e, Before: before, After: after})
 		})
 
 		addDefaultHandler(s, func(s *Session, e *EventChannelGroupJoin) {
@@ -250,19 +335,23 @@ func (s *Session) addDefaultHandlers() {
 		})
 
 		addDefaultHandler(s, func(s *Session, e *EventServerUpdate) {
-			s.State.updateServer(e)
+			before, after := s.State.updateServer(e)
+			s.dispatch("ServerUpdateSnapshot", &EventServerUpdateSnapshot{EventServerUpdate: e, Before: before, After: after})
 		})
 
 		addDefaultHandler(s, func(s *Session, e *EventServerDelete) {
-			s.State.deleteServer(e)
+			before := s.State.deleteServer(e)
+			s.dispatch("ServerDeleteSnapshot", &EventServerDeleteSnapshot{EventServerDelete: e, Before: before})
 		})
 
 		addDefaultHandler(s, func(s *Session, e *EventServerRoleUpdate) {
-			s.State.updateServerRole(e)
+			before, after := s.State.updateServerRole(e)
+			s.dispatch("ServerRoleUpdateSnapshot", &EventServerRoleUpdateSnapshot{EventServerRoleUpdate: e, Before: before, After: after})
 		})
 
 		addDefaultHandler(s, func(s *Session, e *EventServerRoleDelete) {
-			s.State.deleteServerRole(e)
+			before := s.State.deleteServerRole(e)
+			s.dispatch("ServerRoleDeleteSnapshot", &EventServerRoleDeleteSnapshot{EventServerRoleDelete: e, Before: before})
 		})
 
 		addDefaultHandler(s, func(s *Session, e *EventServerRoleRanksUpdate) {
@@ -276,7 +365,8 @@ func (s *Session) addDefaultHandlers() {
 		})
 
 		addDefaultHandler(s, func(s *Session, e *EventServerMemberUpdate) {
-			s.State.updateServerMember(e)
+			before, after := s.State.updateServerMember(e)
+			s.dispatch("ServerMemberUpdateSnapshot", &EventServerMemberUpdateSnapshot{EventServerMemberUpdate: e, Before: before, After: after})
 		})
 	}
 
@@ -285,7 +375,73 @@ func (s *Session) addDefaultHandlers() {
 	// self-guards each branch on its tracking flag.
 	if s.State.TrackServers() || s.State.TrackMembers() {
 		addDefaultHandler(s, func(s *Session, e *EventServerMemberLeave) {
-			s.State.deleteServerMember(e)
+			before := s.State.deleteServerMember(e)
+			s.dispatch("ServerMemberLeaveSnapshot", &EventServerMemberLeaveSnapshot{EventServerMemberLeave: e, Before: before})
+		})
+	}
+
+	// New messages feed both the message cache and the unreads tracker; each self-guards on its tracking flag
+	if s.State.TrackMessages() || s.State.TrackUnreads() {
+		addDefaultHandler(s, func(s *Session, e *EventMessage) {
+			s.State.createMessage(e)
+			s.State.addUnreadMessage(e)
+		})
+	}
+
+	if s.State.TrackUnreads() {
+		addDefaultHandler(s, func(s *Session, e *EventChannelAck) {
+			s.State.readChannel(e)
+		})
+	}
+
+	if s.State.TrackMessages() {
+		addDefaultHandler(s, func(s *Session, e *EventMessageUpdate) {
+			before, after := s.State.updateMessage(e)
+			s.dispatch("MessageUpdateSnapshot", &EventMessageUpdateSnapshot{EventMessageUpdate: e, Before: before, After: after})
+		})
+
+		addDefaultHandler(s, func(s *Session, e *EventMessageAppend) {
+			s.State.appendMessage(e)
+		})
+
+		addDefaultHandler(s, func(s *Session, e *EventMessageReact) {
+			s.State.addMessageReaction(e)
+		})
+
+		addDefaultHandler(s, func(s *Session, e *EventMessageUnreact) {
+			s.State.removeMessageReaction(e)
+		})
+
+		addDefaultHandler(s, func(s *Session, e *EventMessageRemoveReaction) {
+			s.State.clearMessageReaction(e)
+		})
+
+		addDefaultHandler(s, func(s *Session, e *EventMessageDelete) {
+			before := s.State.deleteMessage(e)
+			s.dispatch("MessageDeleteSnapshot", &EventMessageDeleteSnapshot{EventMessageDelete: e, Before: before})
+		})
+
+		addDefaultHandler(s, func(s *Session, e *EventBulkMessageDelete) {
+			before := s.State.deleteMessages(e)
+			s.dispatch("BulkMessageDeleteSnapshot", &EventBulkMessageDeleteSnapshot{EventBulkMessageDelete: e, Before: before})
+		})
+	}
+
+	if s.State.TrackVoiceStates() {
+		addDefaultHandler(s, func(s *Session, e *EventVoiceChannelJoin) {
+			s.State.joinVoiceChannel(e)
+		})
+
+		addDefaultHandler(s, func(s *Session, e *EventVoiceChannelLeave) {
+			s.State.leaveVoiceChannel(e)
+		})
+
+		addDefaultHandler(s, func(s *Session, e *EventVoiceChannelMove) {
+			s.State.moveVoiceChannel(e)
+		})
+
+		addDefaultHandler(s, func(s *Session, e *EventUserVoiceStateUpdate) {
+			s.State.updateVoiceState(e)
 		})
 	}
 
@@ -295,7 +451,8 @@ func (s *Session) addDefaultHandlers() {
 		})
 
 		addDefaultHandler(s, func(s *Session, e *EventEmojiDelete) {
-			s.State.deleteEmoji(e)
+			before := s.State.deleteEmoji(e)
+			s.dispatch("EmojiDeleteSnapshot", &EventEmojiDeleteSnapshot{EventEmojiDelete: e, Before: before})
 		})
 	}
 }
@@ -313,7 +470,7 @@ func handlerName[T any]() string {
 	// todo: look if this is needed
 
 	if t.Kind() != reflect.Ptr {
-		log.Fatalf("handler must be a pointer to a struct")
+		panic("handler must be a pointer to a struct")
 	}
 
 	for t.Kind() == reflect.Ptr {
@@ -321,27 +478,27 @@ func handlerName[T any]() string {
 	}
 
 	if t.Kind() != reflect.Struct {
-		log.Fatalf("expected struct type, got %s", t.Kind())
+		panic(fmt.Sprintf("expected struct type, got %s", t.Kind()))
 	}
 
 	if field, ok := t.FieldByName("Type"); ok {
 		if field.Type.Kind() != reflect.String {
-			log.Fatalf("event struct %s 'Type' field must be a string", t.Name())
+			panic(fmt.Sprintf("event struct %s 'Type' field must be a string", t.Name()))
 		}
 	} else {
-		log.Fatalf("struct %s must have a 'Type' field", t.Name())
+		panic(fmt.Sprintf("struct %s must have a 'Type' field", t.Name()))
 	}
 
 	if !strings.HasPrefix(t.Name(), "Event") {
-		log.Fatalf("struct %s must be prefixed with 'Event'", t.Name())
+		panic(fmt.Sprintf("struct %s must be prefixed with 'Event'", t.Name()))
 	}
 
 	// Convert "EventMessage" struct name to "Message" event name
 	name := strings.TrimPrefix(t.Name(), "Event")
 
 	// Safety check (assuming eventConstructors is defined elsewhere)
-	if _, found := eventConstructors[name]; !found {
-		log.Fatalf("attempting to bind handler for unsupported event type: %s", t.Name())
+	if _, found := eventConstructors[name]; !found && !syntheticEvents[name] {
+		panic(fmt.Sprintf("attempting to bind handler for unsupported event type: %s", t.Name()))
 	}
 
 	return name
@@ -349,13 +506,45 @@ func handlerName[T any]() string {
 
 // AddHandler registers an event handler using generics.
 // It infers the event name from the handler's argument type, removing the need for a type switch.
-func AddHandler[T any](s *Session, handler func(*Session, T)) {
+// The returned func removes the handler; calling it more than once does nothing.
+func AddHandler[T any](s *Session, handler func(*Session, T)) (remove func()) {
 	name := handlerName[T]()
 
-	wrapped := func(s *Session, e any) {
+	id := s.handlerIDs.Add(1)
+
+	s.addUserHandler(name, id, func(s *Session, e any) {
 		handler(s, e.(T))
-	}
+	})
+
+	return sync.OnceFunc(func() {
+		s.removeUserHandler(name, id)
+	})
+}
+
+// AddHandlerOnce registers an event handler that is removed after it is called once.
+// The returned func removes the handler before it is called; calling it afterward does nothing.
+func AddHandlerOnce[T any](s *Session, handler func(*Session, T)) (remove func()) {
+	name := handlerName[T]()
+
+	id := s.handlerIDs.Add(1)
+
+	// Events may be dispatched concurrently; only the first one to get here is handled
+	var fired atomic.Bool
+
+	s.addUserHandler(name, id, func(s *Session, e any) {
+		if fired.CompareAndSwap(false, true) {
+			s.removeUserHandler(name, id)
+			handler(s, e.(T))
+		}
+	})
+
+	return sync.OnceFunc(func() {
+		fired.Store(true) // A dispatch that already loaded the handler mustn't call it anymore
+		s.removeUserHandler(name, id)
+	})
+}
 
+func (s *Session) addUserHandler(name string, id uint64, handler HandlerFunc) {
 	s.handlersMu.Lock()
 	defer s.handlersMu.Unlock()
 
@@ -363,14 +552,60 @@ func AddHandler[T any](s *Session, handler func(*Session, T)) {
 
 	// Fresh slice, not append in place: a live dispatch may still read the old one.
 	existing := next.user[name]
-	combined := make([]func(*Session, any), len(existing)+1)
+	combined := make([]*userHandler, len(existing)+1)
 	copy(combined, existing)
-	combined[len(existing)] = wrapped
+	combined[len(existing)] = &userHandler{id: id, handler: handler, call: chain(next.middleware, handler)}
 	next.user[name] = combined
 
 	s.handlers.Store(next)
 }
 
+func (s *Session) removeUserHandler(name string, id uint64) {
+	s.handlersMu.Lock()
+	defer s.handlersMu.Unlock()
+
+	existing := s.handlers.Load().user[name]
+	index := slices.IndexFunc(existing, func(h *userHandler) bool {
+		return h.id == id
+	})
+	if index < 0 {
+		return
+	}
+
+	next := s.handlers.Load().clone()
+
+	// slices.Delete would edit the old slice in place, which a live dispatch may still read.
+	if len(existing) == 1 {
+		delete(next.user, name) // No handlers left; let the Websocket drop the event before decoding it
+	} else {
+		next.user[name] = slices.Concat(existing[:index], existing[index+1:])
+	}
+
+	s.handlers.Store(next)
+}
+
+// dispatch delivers a synthetic event (see syntheticEvents) to user handlers.
+// It is called from default handlers, so the event reaches the dispatcher before the user handlers of the original event.
+func (s *Session) dispatch(name string, event any) {
+	handlers := s.handlers.Load().user[name]
+	if len(handlers) == 0 {
+		return
+	}
+
+	job := func() {
+		for _, handler := range handlers {
+			handler.call(s, event)
+		}
+	}
+
+	if s.WS == nil {
+		job()
+		return
+	}
+
+	s.WS.dispatcher.run(event, job)
+}
+
 // addDefaultHandler registers a library handler, which runs before user handlers.
 // At most one default handler exists per event type, so it is stored directly.
 func addDefaultHandler[T any](s *Session, handler func(*Session, T)) {
@@ -383,7 +618,7 @@ func addDefaultHandler[T any](s *Session, handler func(*Session, T)) {
 
 	// Catch a development error if we ever overwrite an existing handler; it probably wasn't intentional
 	if _, exists := next.defaults[name]; exists {
-		log.Printf("addDefaultHandler is overwriting for: %T", handler)
+		s.logger().Warn("addDefaultHandler is overwriting an existing handler", "event", name)
 	}
 
 	next.defaults[name] = func(s *Session, e any) {
@@ -397,6 +632,36 @@ func (s *Session) IsConnected() bool {
 	return s.WS != nil && s.WS.IsConnected()
 }
 
+// WaitUntilReady blocks until the connection is ready, ctx is done, or the connection is closed (ErrConnectionClosed).
+// Call it after Open; it suits readiness probes:
+//
+//	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+//	defer cancel()
+//	if err := session.WaitUntilReady(ctx); err != nil {
+//		// not ready
+//	}
+func (s *Session) WaitUntilReady(ctx context.Context) error {
+	if s.WS == nil {
+		return ErrConnectionClosed
+	}
+
+	for {
+		state, changed := s.WS.waitState()
+		switch state {
+		case ConnectionStateReady:
+			return nil
+		case ConnectionStateClosed:
+			return ErrConnectionClosed
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-changed:
+		}
+	}
+}
+
 func (s *Session) buildOpenQueryParams() url.Values {
 	// See: https://developers.stoat.chat/developers/events/establishing#query-parameters
 	// todo: make into a struct with Encode() method?
@@ -404,7 +669,7 @@ func (s *Session) buildOpenQueryParams() url.Values {
 	parameters.Set("token", s.Token)
 	parameters.Set("format", "msgpack")
 	parameters.Set("version", "1")
-	parameters.Set("reconnect", "false") // undocumented: true omits EventReady
+	parameters.Set("reconnect", "false") // undocumented: true omits EventReady; see Websocket.Resume
 
 	// See: https://developers.stoat.chat/developers/events/establishing#ready-fields
 
@@ -428,12 +693,58 @@ func (s *Session) buildOpenQueryParams() url.Values {
 		parameters.Add("ready", "emojis")
 	}
 
-	parameters.Add("ready", "channel_unreads")
+	if s.State.trackVoice {
+		parameters.Add("ready", "voice_states")
+	}
+
+	if s.State.trackUnreads {
+		parameters.Add("ready", "channel_unreads")
+	}
+
 	parameters.Add("ready", "policy_changes")
 
 	return parameters
 }
 
+// ErrFeatureDisabled is returned early by methods needing a service the instance has disabled, such as
+// AttachmentUpload without Autumn, or ChannelsJoinCall without LiveKit. See Session.Instance and its Features.
+var ErrFeatureDisabled = errors.New("feature disabled on this instance")
+
+// Instance returns the configuration of the session's instance, as fetched by Open or FetchInstance, or nil
+// if it hasn't been fetched yet. Its Features tell which services are enabled; treat it as read-only.
+func (s *Session) Instance() *InstanceConfig {
+	return s.instance.Load()
+}
+
+// FetchInstance fetches and caches the configuration of the session's instance, without opening the Websocket.
+// Like Open, it adopts the URLs of the services the instance advertises; see HTTPClient.URLs.
+func (s *Session) FetchInstance() (*InstanceConfig, error) {
+	return s.FetchInstanceCtx(context.Background())
+}
+
+// FetchInstanceCtx is like FetchInstance, but aborts when ctx is cancelled.
+func (s *Session) FetchInstanceCtx(ctx context.Context) (instance *InstanceConfig, err error) {
+	err = s.HTTP.RequestContext(ctx, http.MethodGet, s.HTTP.URLs().API, nil, &instance)
+	if err != nil {
+		return nil, err
+	}
+
+	s.instance.Store(instance)
+	s.HTTP.discover(*instance)
+	return
+}
+
+// requireFeature fails with ErrFeatureDisabled if the instance is known to have the feature disabled.
+// Nothing is fetched for it: until the instance is known, requests are sent and fail on their own.
+func (s *Session) requireFeature(name string, enabled func(features InstanceConfigFeatures) bool) error {
+	instance := s.Instance()
+	if instance == nil || enabled(instance.Features) {
+		return nil
+	}
+
+	return fmt.Errorf("%w: %s", ErrFeatureDisabled, name)
+}
+
 // Open determines the Websocket URL and establishes a connection.
 // It also detects if you are logged in as a user or a bot.
 //
@@ -462,13 +773,12 @@ func (s *Session) Open(configuration ...StateConfig) (err error) {
 	s.addDefaultHandlers()
 
 	// Determine the Websocket URL
-	var instance InstanceConfig
-	err = s.HTTP.Request(http.MethodGet, apiURL, nil, &instance)
+	instance, err := s.FetchInstance()
 	if err != nil {
 		return
 	}
 
-	log.Printf("API version detected: %s\n", instance.Revolt)
+	s.logger().Info("API version detected", "version", instance.Revolt)
 
 	wsURL, err := url.Parse(instance.WS)
 	if err != nil {
@@ -478,7 +788,11 @@ func (s *Session) Open(configuration ...StateConfig) (err error) {
 	wsURL.RawQuery = s.buildOpenQueryParams().Encode()
 
 	s.WS = newWebsocket(s, wsURL.String())
-	return s.WS.connect()
+	if err = s.WS.connect(false); err != nil {
+		s.WS.setState(ConnectionStateClosed)
+	}
+
+	return
 }
 
 // Close closes the Websocket connection and stops background workers.
@@ -510,7 +824,7 @@ func (s *Session) WriteSocketMSGP(data any) error {
 	marshaler, ok := data.(msgp.Marshaler)
 	if !ok {
 		err := fmt.Errorf("%T doesn't implement msgp.Marshaler", data)
-		log.Println(fmt.Errorf("%w. Did you mean to use WriteSocketJSON, or is revoltgo_msgp_gen outdated", err))
+		s.logger().Error("Cannot write MessagePack; did you mean to use WriteSocketJSON, or is revoltgo_msgp_gen outdated?", "err", err)
 		return err
 	}
 
@@ -523,19 +837,33 @@ func (s *Session) WriteSocketMSGP(data any) error {
 }
 
 func (s *Session) AttachmentUpload(file *FileParams) (attachment *FileParamsData, err error) {
+	return s.AttachmentUploadCtx(context.Background(), file)
+}
+
+// AttachmentUploadCtx is like AttachmentUpload, but aborts when ctx is cancelled.
+func (s *Session) AttachmentUploadCtx(ctx context.Context, file *FileParams) (attachment *FileParamsData, err error) {
+
+	if err = s.requireFeature("file uploads (Autumn)", InstanceConfigFeatures.FileUploads); err != nil {
+		return
+	}
 
 	if file.Name == "" {
-		log.Printf("Warning: uploading files without names may cause the media to not load on the client")
+		s.logger().Warn("Uploading files without names may cause the media to not load on the client")
 	}
 
-	endpoint := EndpointAutumn("attachments")
-	err = s.HTTP.Request(http.MethodPost, endpoint, file, &attachment)
+	endpoint := s.HTTP.EndpointAutumn("attachments")
+	err = s.HTTP.RequestContext(ctx, http.MethodPost, endpoint, file, &attachment)
 	return
 }
 
 func (s *Session) Emoji(eID string) (emoji *Emoji, err error) {
+	return s.EmojiCtx(context.Background(), eID)
+}
+
+// EmojiCtx is like Emoji, but aborts when ctx is cancelled.
+func (s *Session) EmojiCtx(ctx context.Context, eID string) (emoji *Emoji, err error) {
 	endpoint := EndpointCustomEmoji(eID)
-	err = s.HTTP.Request(http.MethodGet, endpoint, nil, &emoji)
+	err = s.HTTP.RequestContext(ctx, http.MethodGet, endpoint, nil, &emoji)
 	if err == nil {
 		s.State.addEmoji(emoji)
 
@@ -544,20 +872,35 @@ func (s *Session) Emoji(eID string) (emoji *Emoji, err error) {
 }
 
 func (s *Session) EmojiCreate(eID string, data EmojiCreateParams) (emoji *Emoji, err error) {
+	return s.EmojiCreateCtx(context.Background(), eID, data)
+}
+
+// EmojiCreateCtx is like EmojiCreate, but aborts when ctx is cancelled.
+func (s *Session) EmojiCreateCtx(ctx context.Context, eID string, data EmojiCreateParams) (emoji *Emoji, err error) {
 	endpoint := EndpointCustomEmoji(eID)
-	err = s.HTTP.Request(http.MethodPut, endpoint, data, &emoji)
+	err = s.HTTP.RequestContext(ctx, http.MethodPut, endpoint, data, &emoji)
 	return
 }
 
 func (s *Session) EmojiDelete(eID string) error {
+	return s.EmojiDeleteCtx(context.Background(), eID)
+}
+
+// EmojiDeleteCtx is like EmojiDelete, but aborts when ctx is cancelled.
+func (s *Session) EmojiDeleteCtx(ctx context.Context, eID string) error {
 	endpoint := EndpointCustomEmoji(eID)
-	return s.HTTP.Request(http.MethodDelete, endpoint, nil, nil)
+	return s.HTTP.RequestContext(ctx, http.MethodDelete, endpoint, nil, nil)
 }
 
 // Channel fetches a channel using an API call
 func (s *Session) Channel(cID string) (channel *Channel, err error) {
+	return s.ChannelCtx(context.Background(), cID)
+}
+
+// ChannelCtx is like Channel, but aborts when ctx is cancelled.
+func (s *Session) ChannelCtx(ctx context.Context, cID string) (channel *Channel, err error) {
 	endpoint := EndpointChannel(cID)
-	err = s.HTTP.Request(http.MethodGet, endpoint, nil, &channel)
+	err = s.HTTP.RequestContext(ctx, http.MethodGet, endpoint, nil, &channel)
 	if err == nil {
 		s.State.addChannel(channel)
 	}
@@ -568,8 +911,13 @@ func (s *Session) Channel(cID string) (channel *Channel, err error) {
 // User fetches a user by their ID
 // To fetch self, supply "@me" as the ID
 func (s *Session) User(uID string) (user *User, err error) {
+	return s.UserCtx(context.Background(), uID)
+}
+
+// UserCtx is like User, but aborts when ctx is cancelled.
+func (s *Session) UserCtx(ctx context.Context, uID string) (user *User, err error) {
 	endpoint := EndpointUser(uID)
-	err = s.HTTP.Request(http.MethodGet, endpoint, nil, &user)
+	err = s.HTTP.RequestContext(ctx, http.MethodGet, endpoint, nil, &user)
 	if err == nil {
 		s.State.addUser(user)
 	}
@@ -578,52 +926,92 @@ func (s *Session) User(uID string) (user *User, err error) {
 }
 
 func (s *Session) UserBlock(uID string) (user *User, err error) {
+	return s.UserBlockCtx(context.Background(), uID)
+}
+
+// UserBlockCtx is like UserBlock, but aborts when ctx is cancelled.
+func (s *Session) UserBlockCtx(ctx context.Context, uID string) (user *User, err error) {
 	endpoint := EndpointUserBlock(uID)
-	err = s.HTTP.Request(http.MethodPut, endpoint, nil, &user)
+	err = s.HTTP.RequestContext(ctx, http.MethodPut, endpoint, nil, &user)
 	return
 }
 
 func (s *Session) UserUnblock(uID string) (user *User, err error) {
+	return s.UserUnblockCtx(context.Background(), uID)
+}
+
+// UserUnblockCtx is like UserUnblock, but aborts when ctx is cancelled.
+func (s *Session) UserUnblockCtx(ctx context.Context, uID string) (user *User, err error) {
 	endpoint := EndpointUserBlock(uID)
-	err = s.HTTP.Request(http.MethodDelete, endpoint, nil, &user)
+	err = s.HTTP.RequestContext(ctx, http.MethodDelete, endpoint, nil, &user)
 	return
 }
 
 func (s *Session) UserProfile(uID string) (profile *UserProfile, err error) {
+	return s.UserProfileCtx(context.Background(), uID)
+}
+
+// UserProfileCtx is like UserProfile, but aborts when ctx is cancelled.
+func (s *Session) UserProfileCtx(ctx context.Context, uID string) (profile *UserProfile, err error) {
 	endpoint := EndpointUserProfile(uID)
-	err = s.HTTP.Request(http.MethodGet, endpoint, nil, &profile)
+	err = s.HTTP.RequestContext(ctx, http.MethodGet, endpoint, nil, &profile)
 	return
 }
 
 func (s *Session) UserDefaultAvatar(uID string) (binary []byte, err error) {
+	return s.UserDefaultAvatarCtx(context.Background(), uID)
+}
+
+// UserDefaultAvatarCtx is like UserDefaultAvatar, but aborts when ctx is cancelled.
+func (s *Session) UserDefaultAvatarCtx(ctx context.Context, uID string) (binary []byte, err error) {
 	endpoint := EndpointUserDefaultAvatar(uID)
-	err = s.HTTP.Request(http.MethodGet, endpoint, nil, &binary)
+	err = s.HTTP.RequestContext(ctx, http.MethodGet, endpoint, nil, &binary)
 	return
 }
 
 func (s *Session) SetUsername(data UsernameParams) (user *User, err error) {
-	err = s.HTTP.Request(http.MethodPatch, URLUserMeUsername, data, &user)
+	return s.SetUsernameCtx(context.Background(), data)
+}
+
+// SetUsernameCtx is like SetUsername, but aborts when ctx is cancelled.
+func (s *Session) SetUsernameCtx(ctx context.Context, data UsernameParams) (user *User, err error) {
+	err = s.HTTP.RequestContext(ctx, http.MethodPatch, URLUserMeUsername, data, &user)
 	return
 }
 
 func (s *Session) UserFlags(uID string) (flags int, err error) {
+	return s.UserFlagsCtx(context.Background(), uID)
+}
+
+// UserFlagsCtx is like UserFlags, but aborts when ctx is cancelled.
+func (s *Session) UserFlagsCtx(ctx context.Context, uID string) (flags int, err error) {
 	endpoint := EndpointUserFlags(uID)
-	err = s.HTTP.Request(http.MethodGet, endpoint, nil, &flags)
+	err = s.HTTP.RequestContext(ctx, http.MethodGet, endpoint, nil, &flags)
 	return
 }
 
 func (s *Session) UserEdit(uID string, data UserEditParams) (user *User, err error) {
+	return s.UserEditCtx(context.Background(), uID, data)
+}
+
+// UserEditCtx is like UserEdit, but aborts when ctx is cancelled.
+func (s *Session) UserEditCtx(ctx context.Context, uID string, data UserEditParams) (user *User, err error) {
 	endpoint := EndpointUser(uID)
-	err = s.HTTP.Request(http.MethodPatch, endpoint, data, &user)
+	err = s.HTTP.RequestContext(ctx, http.MethodPatch, endpoint, data, &user)
 	return
 }
 
 // Server fetches a server by its ID
 func (s *Session) Server(id string) (server *Server, err error) {
+	return s.ServerCtx(context.Background(), id)
+}
+
+// ServerCtx is like Server, but aborts when ctx is cancelled.
+func (s *Session) ServerCtx(ctx context.Context, id string) (server *Server, err error) {
 	endpoint := EndpointServer(id)
 	// todo: yep... this exists. Turns channels into object array of channels
 	// endpoint = fmt.Sprintf("%s?include_channels=true", endpoint)
-	err = s.HTTP.Request(http.MethodGet, endpoint, nil, &server)
+	err = s.HTTP.RequestContext(ctx, http.MethodGet, endpoint, nil, &server)
 	if err == nil {
 		s.State.addServer(server)
 	}
@@ -632,15 +1020,25 @@ func (s *Session) Server(id string) (server *Server, err error) {
 }
 
 func (s *Session) ServerEdit(id string, data ServerEditParams) (server *Server, err error) {
+	return s.ServerEditCtx(context.Background(), id, data)
+}
+
+// ServerEditCtx is like ServerEdit, but aborts when ctx is cancelled.
+func (s *Session) ServerEditCtx(ctx context.Context, id string, data ServerEditParams) (server *Server, err error) {
 	endpoint := EndpointServer(id)
-	err = s.HTTP.Request(http.MethodPatch, endpoint, data, &server)
+	err = s.HTTP.RequestContext(ctx, http.MethodPatch, endpoint, data, &server)
 	return
 }
 
 // ServerCreate creates a server based on the data provided
 func (s *Session) ServerCreate(data ServerCreateParams) (server *Server, err error) {
+	return s.ServerCreateCtx(context.Background(), data)
+}
+
+// ServerCreateCtx is like ServerCreate, but aborts when ctx is cancelled.
+func (s *Session) ServerCreateCtx(ctx context.Context, data ServerCreateParams) (server *Server, err error) {
 	endpoint := EndpointServer("create")
-	err = s.HTTP.Request(http.MethodPost, endpoint, data, &server)
+	err = s.HTTP.RequestContext(ctx, http.MethodPost, endpoint, data, &server)
 	return
 }
 
@@ -651,19 +1049,34 @@ func (s *Session) ChannelBeginTyping(cID string) (err error) {
 }
 
 func (s *Session) ChannelSearch(cID string, query ChannelSearchParams) (messages []*Message, err error) {
+	return s.ChannelSearchCtx(context.Background(), cID, query)
+}
+
+// ChannelSearchCtx is like ChannelSearch, but aborts when ctx is cancelled.
+func (s *Session) ChannelSearchCtx(ctx context.Context, cID string, query ChannelSearchParams) (messages []*Message, err error) {
 	endpoint := EndpointChannelSearch(cID)
-	err = s.HTTP.Request(http.MethodPost, endpoint, query, &messages)
+	err = s.HTTP.RequestContext(ctx, http.MethodPost, endpoint, query, &messages)
 	return
 }
 
 func (s *Session) ChannelMessagePin(cID, mID string) (err error) {
+	return s.ChannelMessagePinCtx(context.Background(), cID, mID)
+}
+
+// ChannelMessagePinCtx is like ChannelMessagePin, but aborts when ctx is cancelled.
+func (s *Session) ChannelMessagePinCtx(ctx context.Context, cID, mID string) (err error) {
 	endpoint := EndpointChannelMessagePin(cID, mID)
-	return s.HTTP.Request(http.MethodPost, endpoint, nil, nil)
+	return s.HTTP.RequestContext(ctx, http.MethodPost, endpoint, nil, nil)
 }
 
 func (s *Session) ChannelMessageUnpin(cID, mID string) (err error) {
+	return s.ChannelMessageUnpinCtx(context.Background(), cID, mID)
+}
+
+// ChannelMessageUnpinCtx is like ChannelMessageUnpin, but aborts when ctx is cancelled.
+func (s *Session) ChannelMessageUnpinCtx(ctx context.Context, cID, mID string) (err error) {
 	endpoint := EndpointChannelMessagePin(cID, mID)
-	return s.HTTP.Request(http.MethodDelete, endpoint, nil, nil)
+	return s.HTTP.RequestContext(ctx, http.MethodDelete, endpoint, nil, nil)
 }
 
 // ChannelEndTyping is a Websocket method to stop typing in a channel
@@ -673,56 +1086,101 @@ func (s *Session) ChannelEndTyping(cID string) (err error) {
 }
 
 func (s *Session) ChannelWebhooks(cID string) (webhooks []*Webhook, err error) {
+	return s.ChannelWebhooksCtx(context.Background(), cID)
+}
+
+// ChannelWebhooksCtx is like ChannelWebhooks, but aborts when ctx is cancelled.
+func (s *Session) ChannelWebhooksCtx(ctx context.Context, cID string) (webhooks []*Webhook, err error) {
 	endpoint := EndpointChannelWebhooks(cID)
-	err = s.HTTP.Request(http.MethodGet, endpoint, nil, &webhooks)
+	err = s.HTTP.RequestContext(ctx, http.MethodGet, endpoint, nil, &webhooks)
 	return
 }
 
 func (s *Session) ChannelWebhookCreate(cID string, data WebhookCreateParams) (webhook *Webhook, err error) {
+	return s.ChannelWebhookCreateCtx(context.Background(), cID, data)
+}
+
+// ChannelWebhookCreateCtx is like ChannelWebhookCreate, but aborts when ctx is cancelled.
+func (s *Session) ChannelWebhookCreateCtx(ctx context.Context, cID string, data WebhookCreateParams) (webhook *Webhook, err error) {
 	endpoint := EndpointChannelWebhooks(cID)
-	err = s.HTTP.Request(http.MethodPost, endpoint, data, &webhook)
+	err = s.HTTP.RequestContext(ctx, http.MethodPost, endpoint, data, &webhook)
 	return
 }
 
 // Webhook fetches a webhook using its ID
 func (s *Session) Webhook(wID string) (webhook *Webhook, err error) {
+	return s.WebhookCtx(context.Background(), wID)
+}
+
+// WebhookCtx is like Webhook, but aborts when ctx is cancelled.
+func (s *Session) WebhookCtx(ctx context.Context, wID string) (webhook *Webhook, err error) {
 	endpoint := EndpointWebhook(wID)
-	err = s.HTTP.Request(http.MethodGet, endpoint, nil, &webhook)
+	err = s.HTTP.RequestContext(ctx, http.MethodGet, endpoint, nil, &webhook)
 	return
 }
 
 // WebhookToken fetches a webhook using its ID and token
 func (s *Session) WebhookToken(wID, wToken string) (webhook *Webhook, err error) {
+	return s.WebhookTokenCtx(context.Background(), wID, wToken)
+}
+
+// WebhookTokenCtx is like WebhookToken, but aborts when ctx is cancelled.
+func (s *Session) WebhookTokenCtx(ctx context.Context, wID, wToken string) (webhook *Webhook, err error) {
 	endpoint := EndpointWebhookToken(wID, wToken)
-	err = s.HTTP.Request(http.MethodGet, endpoint, nil, &webhook)
+	err = s.HTTP.RequestContext(ctx, http.MethodGet, endpoint, nil, &webhook)
 	return
 }
 
 func (s *Session) WebhookTokenExecute(wID, wToken string, data WebhookExecuteParams) (message *Message, err error) {
+	return s.WebhookTokenExecuteCtx(context.Background(), wID, wToken, data)
+}
+
+// WebhookTokenExecuteCtx is like WebhookTokenExecute, but aborts when ctx is cancelled.
+func (s *Session) WebhookTokenExecuteCtx(ctx context.Context, wID, wToken string, data WebhookExecuteParams) (message *Message, err error) {
 	endpoint := EndpointWebhookToken(wID, wToken)
-	err = s.HTTP.Request(http.MethodPost, endpoint, data, &message)
+	err = s.HTTP.RequestContext(ctx, http.MethodPost, endpoint, data, &message)
 	return
 }
 
 func (s *Session) WebhookDelete(wID string) (err error) {
+	return s.WebhookDeleteCtx(context.Background(), wID)
+}
+
+// WebhookDeleteCtx is like WebhookDelete, but aborts when ctx is cancelled.
+func (s *Session) WebhookDeleteCtx(ctx context.Context, wID string) (err error) {
 	endpoint := EndpointWebhook(wID)
-	return s.HTTP.Request(http.MethodDelete, endpoint, nil, nil)
+	return s.HTTP.RequestContext(ctx, http.MethodDelete, endpoint, nil, nil)
 }
 
 func (s *Session) WebhookTokenDelete(wID, wToken string) (err error) {
+	return s.WebhookTokenDeleteCtx(context.Background(), wID, wToken)
+}
+
+// WebhookTokenDeleteCtx is like WebhookTokenDelete, but aborts when ctx is cancelled.
+func (s *Session) WebhookTokenDeleteCtx(ctx context.Context, wID, wToken string) (err error) {
 	endpoint := EndpointWebhookToken(wID, wToken)
-	return s.HTTP.Request(http.MethodDelete, endpoint, nil, nil)
+	return s.HTTP.RequestContext(ctx, http.MethodDelete, endpoint, nil, nil)
 }
 
 func (s *Session) WebhookEdit(wID string, data WebhookEditParams) (webhook *Webhook, err error) {
+	return s.WebhookEditCtx(context.Background(), wID, data)
+}
+
+// WebhookEditCtx is like WebhookEdit, but aborts when ctx is cancelled.
+func (s *Session) WebhookEditCtx(ctx context.Context, wID string, data WebhookEditParams) (webhook *Webhook, err error) {
 	endpoint := EndpointWebhook(wID)
-	err = s.HTTP.Request(http.MethodPatch, endpoint, data, &webhook)
+	err = s.HTTP.RequestContext(ctx, http.MethodPatch, endpoint, data, &webhook)
 	return
 }
 
 func (s *Session) WebhookTokenEdit(wID, wToken string, data WebhookEditParams) (webhook *Webhook, err error) {
+	return s.WebhookTokenEditCtx(context.Background(), wID, wToken, data)
+}
+
+// WebhookTokenEditCtx is like WebhookTokenEdit, but aborts when ctx is cancelled.
+func (s *Session) WebhookTokenEditCtx(ctx context.Context, wID, wToken string, data WebhookEditParams) (webhook *Webhook, err error) {
 	endpoint := EndpointWebhookToken(wID, wToken)
-	err = s.HTTP.Request(http.MethodPatch, endpoint, data, &webhook)
+	err = s.HTTP.RequestContext(ctx, http.MethodPatch, endpoint, data, &webhook)
 	return
 }
 
@@ -736,176 +1194,325 @@ func (s *Session) WebhookTokenExecuteGitHub(wID, wToken, githubEventName string,
 // GroupCreate creates a group based on the data provided
 // "Users" field is a list of user IDs that will be in the group
 func (s *Session) GroupCreate(data GroupCreateParams) (group *Group, err error) {
+	return s.GroupCreateCtx(context.Background(), data)
+}
+
+// GroupCreateCtx is like GroupCreate, but aborts when ctx is cancelled.
+func (s *Session) GroupCreateCtx(ctx context.Context, data GroupCreateParams) (group *Group, err error) {
 	endpoint := EndpointChannel("create")
-	err = s.HTTP.Request(http.MethodPost, endpoint, data, &group)
+	err = s.HTTP.RequestContext(ctx, http.MethodPost, endpoint, data, &group)
 	return
 }
 
 func (s *Session) GroupMemberAdd(cID, mID string) (err error) {
+	return s.GroupMemberAddCtx(context.Background(), cID, mID)
+}
+
+// GroupMemberAddCtx is like GroupMemberAdd, but aborts when ctx is cancelled.
+func (s *Session) GroupMemberAddCtx(ctx context.Context, cID, mID string) (err error) {
 	endpoint := EndpointChannelRecipients(cID, mID)
-	err = s.HTTP.Request(http.MethodPut, endpoint, nil, nil)
+	err = s.HTTP.RequestContext(ctx, http.MethodPut, endpoint, nil, nil)
 	return
 }
 
 func (s *Session) GroupMemberDelete(cID, mID string) (err error) {
+	return s.GroupMemberDeleteCtx(context.Background(), cID, mID)
+}
+
+// GroupMemberDeleteCtx is like GroupMemberDelete, but aborts when ctx is cancelled.
+func (s *Session) GroupMemberDeleteCtx(ctx context.Context, cID, mID string) (err error) {
 	endpoint := EndpointChannelRecipients(cID, mID)
-	err = s.HTTP.Request(http.MethodDelete, endpoint, nil, nil)
+	err = s.HTTP.RequestContext(ctx, http.MethodDelete, endpoint, nil, nil)
 	return
 }
 
 func (s *Session) GroupMembers(cID string) (users []*User, err error) {
+	return s.GroupMembersCtx(context.Background(), cID)
+}
+
+// GroupMembersCtx is like GroupMembers, but aborts when ctx is cancelled.
+func (s *Session) GroupMembersCtx(ctx context.Context, cID string) (users []*User, err error) {
 	endpoint := EndpointChannelMembers(cID)
-	err = s.HTTP.Request(http.MethodGet, endpoint, nil, &users)
+	err = s.HTTP.RequestContext(ctx, http.MethodGet, endpoint, nil, &users)
 	return
 }
 
 func (s *Session) ChannelInviteCreate(cID string) (invite *InviteCreate, err error) {
+	return s.ChannelInviteCreateCtx(context.Background(), cID)
+}
+
+// ChannelInviteCreateCtx is like ChannelInviteCreate, but aborts when ctx is cancelled.
+func (s *Session) ChannelInviteCreateCtx(ctx context.Context, cID string) (invite *InviteCreate, err error) {
 	endpoint := EndpointChannelInvites(cID)
-	err = s.HTTP.Request(http.MethodPost, endpoint, nil, &invite)
+	err = s.HTTP.RequestContext(ctx, http.MethodPost, endpoint, nil, &invite)
 	return
 }
 
 func (s *Session) ChannelDelete(cID string) (err error) {
+	return s.ChannelDeleteCtx(context.Background(), cID)
+}
+
+// ChannelDeleteCtx is like ChannelDelete, but aborts when ctx is cancelled.
+func (s *Session) ChannelDeleteCtx(ctx context.Context, cID string) (err error) {
 	endpoint := EndpointChannel(cID)
-	err = s.HTTP.Request(http.MethodDelete, endpoint, nil, nil)
+	err = s.HTTP.RequestContext(ctx, http.MethodDelete, endpoint, nil, nil)
 	return
 }
 
 func (s *Session) MessageAck(channelID, messageID string) (err error) {
+	return s.MessageAckCtx(context.Background(), channelID, messageID)
+}
+
+// MessageAckCtx is like MessageAck, but aborts when ctx is cancelled.
+func (s *Session) MessageAckCtx(ctx context.Context, channelID, messageID string) (err error) {
 	endpoint := EndpointChannelAckMessage(channelID, messageID)
-	err = s.HTTP.Request(http.MethodPut, endpoint, nil, nil)
+	err = s.HTTP.RequestContext(ctx, http.MethodPut, endpoint, nil, nil)
+	if err == nil {
+		s.State.ackChannel(channelID, messageID)
+	}
+
 	return
 }
 
 func (s *Session) ServerBans(sID string) (bans []*ServerBans, err error) {
+	return s.ServerBansCtx(context.Background(), sID)
+}
+
+// ServerBansCtx is like ServerBans, but aborts when ctx is cancelled.
+func (s *Session) ServerBansCtx(ctx context.Context, sID string) (bans []*ServerBans, err error) {
 	endpoint := EndpointServerBans(sID)
-	err = s.HTTP.Request(http.MethodGet, endpoint, nil, &bans)
+	err = s.HTTP.RequestContext(ctx, http.MethodGet, endpoint, nil, &bans)
 	return
 }
 
 func (s *Session) ServerAck(serverID string) (err error) {
+	return s.ServerAckCtx(context.Background(), serverID)
+}
+
+// ServerAckCtx is like ServerAck, but aborts when ctx is cancelled.
+func (s *Session) ServerAckCtx(ctx context.Context, serverID string) (err error) {
 	endpoint := EndpointServerAck(serverID)
-	err = s.HTTP.Request(http.MethodPut, endpoint, nil, nil)
+	err = s.HTTP.RequestContext(ctx, http.MethodPut, endpoint, nil, nil)
 	return
 }
 
 func (s *Session) ServerInvites(sID string) (invites []*Invite, err error) {
+	return s.ServerInvitesCtx(context.Background(), sID)
+}
+
+// ServerInvitesCtx is like ServerInvites, but aborts when ctx is cancelled.
+func (s *Session) ServerInvitesCtx(ctx context.Context, sID string) (invites []*Invite, err error) {
 	endpoint := EndpointServerInvites(sID)
-	err = s.HTTP.Request(http.MethodGet, endpoint, nil, &invites)
+	err = s.HTTP.RequestContext(ctx, http.MethodGet, endpoint, nil, &invites)
 	return
 }
 
 func (s *Session) ServersRole(sID, rID string) (role *ServerRole, err error) {
+	return s.ServersRoleCtx(context.Background(), sID, rID)
+}
+
+// ServersRoleCtx is like ServersRole, but aborts when ctx is cancelled.
+func (s *Session) ServersRoleCtx(ctx context.Context, sID, rID string) (role *ServerRole, err error) {
 	endpoint := EndpointServerRole(sID, rID)
-	err = s.HTTP.Request(http.MethodGet, endpoint, nil, &role)
+	err = s.HTTP.RequestContext(ctx, http.MethodGet, endpoint, nil, &role)
 	return
 }
 
 func (s *Session) Invite(iID string) (invite *Invite, err error) {
+	return s.InviteCtx(context.Background(), iID)
+}
+
+// InviteCtx is like Invite, but aborts when ctx is cancelled.
+func (s *Session) InviteCtx(ctx context.Context, iID string) (invite *Invite, err error) {
 	endpoint := EndpointInvite(iID)
-	err = s.HTTP.Request(http.MethodGet, endpoint, nil, &invite)
+	err = s.HTTP.RequestContext(ctx, http.MethodGet, endpoint, nil, &invite)
 	return
 }
 
 func (s *Session) InviteJoin(iID string) (invite *Invite, err error) {
+	return s.InviteJoinCtx(context.Background(), iID)
+}
+
+// InviteJoinCtx is like InviteJoin, but aborts when ctx is cancelled.
+func (s *Session) InviteJoinCtx(ctx context.Context, iID string) (invite *Invite, err error) {
 	endpoint := EndpointInvite(iID)
-	err = s.HTTP.Request(http.MethodPost, endpoint, nil, &invite)
+	err = s.HTTP.RequestContext(ctx, http.MethodPost, endpoint, nil, &invite)
 	return
 }
 
 func (s *Session) InviteDelete(iID string) (err error) {
+	return s.InviteDeleteCtx(context.Background(), iID)
+}
+
+// InviteDeleteCtx is like InviteDelete, but aborts when ctx is cancelled.
+func (s *Session) InviteDeleteCtx(ctx context.Context, iID string) (err error) {
 	endpoint := EndpointInvite(iID)
-	err = s.HTTP.Request(http.MethodDelete, endpoint, nil, nil)
+	err = s.HTTP.RequestContext(ctx, http.MethodDelete, endpoint, nil, nil)
 	return
 }
 
 func (s *Session) ServerRoleDelete(sID, rID string) (err error) {
+	return s.ServerRoleDeleteCtx(context.Background(), sID, rID)
+}
+
+// ServerRoleDeleteCtx is like ServerRoleDelete, but aborts when ctx is cancelled.
+func (s *Session) ServerRoleDeleteCtx(ctx context.Context, sID, rID string) (err error) {
 	endpoint := EndpointServerRole(sID, rID)
-	err = s.HTTP.Request(http.MethodDelete, endpoint, nil, nil)
+	err = s.HTTP.RequestContext(ctx, http.MethodDelete, endpoint, nil, nil)
 	return
 }
 
 func (s *Session) ServerRoleEdit(sID, rID string, data ServerRoleEditParams) (role *ServerRole, err error) {
+	return s.ServerRoleEditCtx(context.Background(), sID, rID, data)
+}
+
+// ServerRoleEditCtx is like ServerRoleEdit, but aborts when ctx is cancelled.
+func (s *Session) ServerRoleEditCtx(ctx context.Context, sID, rID string, data ServerRoleEditParams) (role *ServerRole, err error) {
 	endpoint := EndpointServerRole(sID, rID)
-	err = s.HTTP.Request(http.MethodPatch, endpoint, data, &role)
+	err = s.HTTP.RequestContext(ctx, http.MethodPatch, endpoint, data, &role)
 	return
 }
 
 func (s *Session) ServerEmojis(sID string) (emojis []*Emoji, err error) {
+	return s.ServerEmojisCtx(context.Background(), sID)
+}
+
+// ServerEmojisCtx is like ServerEmojis, but aborts when ctx is cancelled.
+func (s *Session) ServerEmojisCtx(ctx context.Context, sID string) (emojis []*Emoji, err error) {
 	endpoint := EndpointServerEmojis(sID)
-	err = s.HTTP.Request(http.MethodGet, endpoint, nil, &emojis)
+	err = s.HTTP.RequestContext(ctx, http.MethodGet, endpoint, nil, &emojis)
 	return
 }
 
 func (s *Session) ServersRoleRanksEdit(sID string, ranks []string) (err error) {
+	return s.ServersRoleRanksEditCtx(context.Background(), sID, ranks)
+}
+
+// ServersRoleRanksEditCtx is like ServersRoleRanksEdit, but aborts when ctx is cancelled.
+func (s *Session) ServersRoleRanksEditCtx(ctx context.Context, sID string, ranks []string) (err error) {
 	endpoint := EndpointServerRolesRanks(sID)
-	err = s.HTTP.Request(http.MethodPatch, endpoint, ranks, nil)
+	err = s.HTTP.RequestContext(ctx, http.MethodPatch, endpoint, ranks, nil)
 	return
 }
 
 func (s *Session) ServersRoleCreate(sID string, data ServerRoleCreateParams) (role *ServerRole, err error) {
+	return s.ServersRoleCreateCtx(context.Background(), sID, data)
+}
+
+// ServersRoleCreateCtx is like ServersRoleCreate, but aborts when ctx is cancelled.
+func (s *Session) ServersRoleCreateCtx(ctx context.Context, sID string, data ServerRoleCreateParams) (role *ServerRole, err error) {
 	endpoint := EndpointServerRoles(sID)
-	err = s.HTTP.Request(http.MethodPost, endpoint, data, &role)
+	err = s.HTTP.RequestContext(ctx, http.MethodPost, endpoint, data, &role)
 	return
 }
 
 func (s *Session) PermissionsSet(sID, rID string, data PermissionOverwrite) (err error) {
+	return s.PermissionsSetCtx(context.Background(), sID, rID, data)
+}
+
+// PermissionsSetCtx is like PermissionsSet, but aborts when ctx is cancelled.
+func (s *Session) PermissionsSetCtx(ctx context.Context, sID, rID string, data PermissionOverwrite) (err error) {
 	endpoint := EndpointServerPermissions(sID, rID)
-	err = s.HTTP.Request(http.MethodPut, endpoint, data, nil)
+	err = s.HTTP.RequestContext(ctx, http.MethodPut, endpoint, data, nil)
 	return
 }
 
 // ChannelPermissionsSet sets permissions for the specified role in this channel.
 func (s *Session) ChannelPermissionsSet(cID, rID string, data PermissionOverwrite) (err error) {
+	return s.ChannelPermissionsSetCtx(context.Background(), cID, rID, data)
+}
+
+// ChannelPermissionsSetCtx is like ChannelPermissionsSet, but aborts when ctx is cancelled.
+func (s *Session) ChannelPermissionsSetCtx(ctx context.Context, cID, rID string, data PermissionOverwrite) (err error) {
 	endpoint := EndpointChannelPermission(cID, rID)
-	return s.HTTP.Request(http.MethodPut, endpoint, data, nil)
+	return s.HTTP.RequestContext(ctx, http.MethodPut, endpoint, data, nil)
 }
 
 // ChannelPermissionsSetDefault sets permissions for the default role in this channel.
 func (s *Session) ChannelPermissionsSetDefault(cID string, data PermissionOverwrite) (err error) {
-	return s.ChannelPermissionsSet(cID, "default", data)
+	return s.ChannelPermissionsSetDefaultCtx(context.Background(), cID, data)
+}
+
+// ChannelPermissionsSetDefaultCtx is like ChannelPermissionsSetDefault, but aborts when ctx is cancelled.
+func (s *Session) ChannelPermissionsSetDefaultCtx(ctx context.Context, cID string, data PermissionOverwrite) (err error) {
+	return s.ChannelPermissionsSetCtx(ctx, cID, "default", data)
 }
 
 // PermissionsSetDefault sets the permissions of a role in a server
 func (s *Session) PermissionsSetDefault(sID string, data PermissionsSetDefaultParams) (err error) {
+	return s.PermissionsSetDefaultCtx(context.Background(), sID, data)
+}
+
+// PermissionsSetDefaultCtx is like PermissionsSetDefault, but aborts when ctx is cancelled.
+func (s *Session) PermissionsSetDefaultCtx(ctx context.Context, sID string, data PermissionsSetDefaultParams) (err error) {
 	endpoint := EndpointServerPermissions(sID, "default")
-	err = s.HTTP.Request(http.MethodPut, endpoint, data, nil)
+	err = s.HTTP.RequestContext(ctx, http.MethodPut, endpoint, data, nil)
 	return
 }
 
 func (s *Session) ChannelEdit(cID string, data ChannelEditParams) (channel *Channel, err error) {
+	return s.ChannelEditCtx(context.Background(), cID, data)
+}
+
+// ChannelEditCtx is like ChannelEdit, but aborts when ctx is cancelled.
+func (s *Session) ChannelEditCtx(ctx context.Context, cID string, data ChannelEditParams) (channel *Channel, err error) {
 	endpoint := EndpointChannel(cID)
-	err = s.HTTP.Request(http.MethodPatch, endpoint, data, &channel)
+	err = s.HTTP.RequestContext(ctx, http.MethodPatch, endpoint, data, &channel)
 	return
 }
 
 func (s *Session) ServerMemberUnban(sID, mID string) (err error) {
+	return s.ServerMemberUnbanCtx(context.Background(), sID, mID)
+}
+
+// ServerMemberUnbanCtx is like ServerMemberUnban, but aborts when ctx is cancelled.
+func (s *Session) ServerMemberUnbanCtx(ctx context.Context, sID, mID string) (err error) {
 	endpoint := EndpointServerBan(sID, mID)
-	err = s.HTTP.Request(http.MethodDelete, endpoint, nil, nil)
+	err = s.HTTP.RequestContext(ctx, http.MethodDelete, endpoint, nil, nil)
 	return
 }
 
 func (s *Session) ServerMemberBan(sID, mID string, data ServerMemberBanParams) (err error) {
+	return s.ServerMemberBanCtx(context.Background(), sID, mID, data)
+}
+
+// ServerMemberBanCtx is like ServerMemberBan, but aborts when ctx is cancelled.
+func (s *Session) ServerMemberBanCtx(ctx context.Context, sID, mID string, data ServerMemberBanParams) (err error) {
 	endpoint := EndpointServerBan(sID, mID)
-	err = s.HTTP.Request(http.MethodPut, endpoint, data, nil)
+	err = s.HTTP.RequestContext(ctx, http.MethodPut, endpoint, data, nil)
 	return
 }
 
 func (s *Session) ServerMemberDelete(sID, mID string) (err error) {
+	return s.ServerMemberDeleteCtx(context.Background(), sID, mID)
+}
+
+// ServerMemberDeleteCtx is like ServerMemberDelete, but aborts when ctx is cancelled.
+func (s *Session) ServerMemberDeleteCtx(ctx context.Context, sID, mID string) (err error) {
 	endpoint := EndpointServerMember(sID, mID)
-	err = s.HTTP.Request(http.MethodDelete, endpoint, nil, nil)
+	err = s.HTTP.RequestContext(ctx, http.MethodDelete, endpoint, nil, nil)
 	return
 }
 
 func (s *Session) ServerMemberEdit(sID, mID string, data ServerMemberEditParams) (member *ServerMember, err error) {
+	return s.ServerMemberEditCtx(context.Background(), sID, mID, data)
+}
+
+// ServerMemberEditCtx is like ServerMemberEdit, but aborts when ctx is cancelled.
+func (s *Session) ServerMemberEditCtx(ctx context.Context, sID, mID string, data ServerMemberEditParams) (member *ServerMember, err error) {
 	endpoint := EndpointServerMember(sID, mID)
-	err = s.HTTP.Request(http.MethodPatch, endpoint, data, &member)
+	err = s.HTTP.RequestContext(ctx, http.MethodPatch, endpoint, data, &member)
 	return
 }
 
 func (s *Session) ServerMember(sID, mID string) (member *ServerMember, err error) {
+	return s.ServerMemberCtx(context.Background(), sID, mID)
+}
+
+// ServerMemberCtx is like ServerMember, but aborts when ctx is cancelled.
+func (s *Session) ServerMemberCtx(ctx context.Context, sID, mID string) (member *ServerMember, err error) {
 	endpoint := EndpointServerMember(sID, mID)
-	err = s.HTTP.Request(http.MethodGet, endpoint, nil, &member)
+	err = s.HTTP.RequestContext(ctx, http.MethodGet, endpoint, nil, &member)
 	if err == nil {
 		s.State.addServerMember(member)
 	}
@@ -914,8 +1521,13 @@ func (s *Session) ServerMember(sID, mID string) (member *ServerMember, err error
 }
 
 func (s *Session) ServerMembers(sID string, excludeOffline bool) (data *ServerMembers, err error) {
+	return s.ServerMembersCtx(context.Background(), sID, excludeOffline)
+}
+
+// ServerMembersCtx is like ServerMembers, but aborts when ctx is cancelled.
+func (s *Session) ServerMembersCtx(ctx context.Context, sID string, excludeOffline bool) (data *ServerMembers, err error) {
 	endpoint := EndpointServerMembers(sID, excludeOffline)
-	err = s.HTTP.Request(http.MethodGet, endpoint, nil, &data)
+	err = s.HTTP.RequestContext(ctx, http.MethodGet, endpoint, nil, &data)
 	if err == nil {
 		s.State.addServerMembersAndUsers(data.Users, data.Members)
 	}
@@ -924,35 +1536,68 @@ func (s *Session) ServerMembers(sID string, excludeOffline bool) (data *ServerMe
 }
 
 func (s *Session) ChannelMessage(cID, mID string) (message *Message, err error) {
+	return s.ChannelMessageCtx(context.Background(), cID, mID)
+}
+
+// ChannelMessageCtx is like ChannelMessage, but aborts when ctx is cancelled.
+func (s *Session) ChannelMessageCtx(ctx context.Context, cID, mID string) (message *Message, err error) {
 	endpoint := EndpointChannelMessage(cID, mID)
-	err = s.HTTP.Request(http.MethodGet, endpoint, nil, &message)
+	err = s.HTTP.RequestContext(ctx, http.MethodGet, endpoint, nil, &message)
+	if err == nil {
+		s.State.addMessage(message)
+	}
+
 	return
 }
 
 // ChannelMessageReactionCreate adds a reaction (emoji ID) to a message
 func (s *Session) ChannelMessageReactionCreate(cID, mID, eID string) (err error) {
+	return s.ChannelMessageReactionCreateCtx(context.Background(), cID, mID, eID)
+}
+
+// ChannelMessageReactionCreateCtx is like ChannelMessageReactionCreate, but aborts when ctx is cancelled.
+func (s *Session) ChannelMessageReactionCreateCtx(ctx context.Context, cID, mID, eID string) (err error) {
 	endpoint := EndpointChannelMessageReaction(cID, mID, eID)
-	err = s.HTTP.Request(http.MethodPut, endpoint, nil, nil)
+	err = s.HTTP.RequestContext(ctx, http.MethodPut, endpoint, nil, nil)
 	return
 }
 
 // ChannelMessageReactionDelete deletes a singular reaction on a message
 func (s *Session) ChannelMessageReactionDelete(cID, mID, eID string) (err error) {
+	return s.ChannelMessageReactionDeleteCtx(context.Background(), cID, mID, eID)
+}
+
+// ChannelMessageReactionDeleteCtx is like ChannelMessageReactionDelete, but aborts when ctx is cancelled.
+func (s *Session) ChannelMessageReactionDeleteCtx(ctx context.Context, cID, mID, eID string) (err error) {
 	endpoint := EndpointChannelMessageReaction(cID, mID, eID)
-	err = s.HTTP.Request(http.MethodDelete, endpoint, nil, nil)
+	err = s.HTTP.RequestContext(ctx, http.MethodDelete, endpoint, nil, nil)
 	return
 }
 
 // ChannelMessageReactionClear clears all reactions on a message
 func (s *Session) ChannelMessageReactionClear(cID, mID string) (err error) {
+	return s.ChannelMessageReactionClearCtx(context.Background(), cID, mID)
+}
+
+// ChannelMessageReactionClearCtx is like ChannelMessageReactionClear, but aborts when ctx is cancelled.
+func (s *Session) ChannelMessageReactionClearCtx(ctx context.Context, cID, mID string) (err error) {
 	endpoint := EndpointChannelMessageReactions(cID, mID)
-	return s.HTTP.Request(http.MethodDelete, endpoint, nil, nil)
+	return s.HTTP.RequestContext(ctx, http.MethodDelete, endpoint, nil, nil)
 }
 
 // ChannelsJoinCall asks the voice server for a token to join the call.
 func (s *Session) ChannelsJoinCall(cID string, data ChannelJoinCallParams) (call ChannelJoinCall, err error) {
+	return s.ChannelsJoinCallCtx(context.Background(), cID, data)
+}
+
+// ChannelsJoinCallCtx is like ChannelsJoinCall, but aborts when ctx is cancelled.
+func (s *Session) ChannelsJoinCallCtx(ctx context.Context, cID string, data ChannelJoinCallParams) (call ChannelJoinCall, err error) {
+	if err = s.requireFeature("voice (LiveKit)", InstanceConfigFeatures.Voice); err != nil {
+		return
+	}
+
 	endpoint := EndpointChannelJoinCall(cID)
-	err = s.HTTP.Request(http.MethodPost, endpoint, data, &call)
+	err = s.HTTP.RequestContext(ctx, http.MethodPost, endpoint, data, &call)
 	return
 }
 
@@ -960,22 +1605,46 @@ func (s *Session) ChannelsJoinCall(cID string, data ChannelJoinCallParams) (call
 // Only works within DMs and groups; returns NoEffect in servers.
 // Returns NotFound if the user is not in the DM/group channel.
 func (s *Session) ChannelsEndRing(cID, uID string) error {
+	return s.ChannelsEndRingCtx(context.Background(), cID, uID)
+}
+
+// ChannelsEndRingCtx is like ChannelsEndRing, but aborts when ctx is cancelled.
+func (s *Session) ChannelsEndRingCtx(ctx context.Context, cID, uID string) error {
+	if err := s.requireFeature("voice (LiveKit)", InstanceConfigFeatures.Voice); err != nil {
+		return err
+	}
+
 	endpoint := EndpointChannelEndRing(cID, uID)
-	return s.HTTP.Request(http.MethodPut, endpoint, nil, nil)
+	return s.HTTP.RequestContext(ctx, http.MethodPut, endpoint, nil, nil)
 }
 
 func (s *Session) ServerChannelCreate(sID string, data ServerChannelCreateParams) (channel *Channel, err error) {
+	return s.ServerChannelCreateCtx(context.Background(), sID, data)
+}
+
+// ServerChannelCreateCtx is like ServerChannelCreate, but aborts when ctx is cancelled.
+func (s *Session) ServerChannelCreateCtx(ctx context.Context, sID string, data ServerChannelCreateParams) (channel *Channel, err error) {
 	endpoint := EndpointServerChannels(sID)
-	err = s.HTTP.Request(http.MethodPost, endpoint, data, &channel)
+	err = s.HTTP.RequestContext(ctx, http.MethodPost, endpoint, data, &channel)
 	return
 }
 
 func (s *Session) ServerDelete(sID string) error {
+	return s.ServerDeleteCtx(context.Background(), sID)
+}
+
+// ServerDeleteCtx is like ServerDelete, but aborts when ctx is cancelled.
+func (s *Session) ServerDeleteCtx(ctx context.Context, sID string) error {
 	endpoint := EndpointServer(sID)
-	return s.HTTP.Request(http.MethodDelete, endpoint, nil, nil)
+	return s.HTTP.RequestContext(ctx, http.MethodDelete, endpoint, nil, nil)
 }
 
 func (s *Session) ChannelMessages(cID string, params ...ChannelMessagesParams) (data ChannelMessages, err error) {
+	return s.ChannelMessagesCtx(context.Background(), cID, params...)
+}
+
+// ChannelMessagesCtx is like ChannelMessages, but aborts when ctx is cancelled.
+func (s *Session) ChannelMessagesCtx(ctx context.Context, cID string, params ...ChannelMessagesParams) (data ChannelMessages, err error) {
 
 	/*
 		This method is special. It has to deal with the following bullshit:
@@ -994,9 +1663,10 @@ func (s *Session) ChannelMessages(cID string, params ...ChannelMessagesParams) (
 	if hasParams {
 		endpoint = fmt.Sprintf("%s?%s", endpoint, params[0].Encode())
 		if params[0].IncludeUsers {
-			err = s.HTTP.Request(http.MethodGet, endpoint, nil, &data)
+			err = s.HTTP.RequestContext(ctx, http.MethodGet, endpoint, nil, &data)
 			if err == nil {
 				s.State.addServerMembersAndUsers(data.Users, data.Members)
+				s.State.addMessages(data.Messages)
 			}
 
 			return
@@ -1004,134 +1674,245 @@ func (s *Session) ChannelMessages(cID string, params ...ChannelMessagesParams) (
 	}
 
 	var messages []*Message
-	if err = s.HTTP.Request(http.MethodGet, endpoint, nil, &messages); err == nil {
+	if err = s.HTTP.RequestContext(ctx, http.MethodGet, endpoint, nil, &messages); err == nil {
 		data.Messages = messages
+		s.State.addMessages(messages)
 	}
 
 	return
 }
 
 func (s *Session) ChannelMessageEdit(cID, mID string, data MessageEditParams) (message *Message, err error) {
+	return s.ChannelMessageEditCtx(context.Background(), cID, mID, data)
+}
+
+// ChannelMessageEditCtx is like ChannelMessageEdit, but aborts when ctx is cancelled.
+func (s *Session) ChannelMessageEditCtx(ctx context.Context, cID, mID string, data MessageEditParams) (message *Message, err error) {
 	endpoint := EndpointChannelMessage(cID, mID)
-	err = s.HTTP.Request(http.MethodPatch, endpoint, data, &message)
+	err = s.HTTP.RequestContext(ctx, http.MethodPatch, endpoint, data, &message)
 	return
 }
 
 func (s *Session) ChannelMessageSend(cID string, data MessageSend) (message *Message, err error) {
+	return s.ChannelMessageSendCtx(context.Background(), cID, data)
+}
+
+// ChannelMessageSendCtx is like ChannelMessageSend, but aborts when ctx is cancelled.
+func (s *Session) ChannelMessageSendCtx(ctx context.Context, cID string, data MessageSend) (message *Message, err error) {
 	endpoint := EndpointChannelMessages(cID)
-	err = s.HTTP.Request(http.MethodPost, endpoint, data, &message)
+	err = s.HTTP.RequestContext(ctx, http.MethodPost, endpoint, data, &message)
 	return
 }
 
 func (s *Session) ChannelMessageDelete(cID, mID string) error {
+	return s.ChannelMessageDeleteCtx(context.Background(), cID, mID)
+}
+
+// ChannelMessageDeleteCtx is like ChannelMessageDelete, but aborts when ctx is cancelled.
+func (s *Session) ChannelMessageDeleteCtx(ctx context.Context, cID, mID string) error {
 	endpoint := EndpointChannelMessage(cID, mID)
-	return s.HTTP.Request(http.MethodDelete, endpoint, nil, nil)
+	return s.HTTP.RequestContext(ctx, http.MethodDelete, endpoint, nil, nil)
 }
 
 func (s *Session) ChannelMessageDeleteBulk(cID string, messages ChannelMessageBulkDeleteParams) error {
+	return s.ChannelMessageDeleteBulkCtx(context.Background(), cID, messages)
+}
+
+// ChannelMessageDeleteBulkCtx is like ChannelMessageDeleteBulk, but aborts when ctx is cancelled.
+func (s *Session) ChannelMessageDeleteBulkCtx(ctx context.Context, cID string, messages ChannelMessageBulkDeleteParams) error {
 	endpoint := EndpointChannelMessage(cID, "bulk")
-	return s.HTTP.Request(http.MethodDelete, endpoint, messages, nil)
+	return s.HTTP.RequestContext(ctx, http.MethodDelete, endpoint, messages, nil)
 }
 
 func (s *Session) AccountCreate(data AccountCreateParams) error {
+	return s.AccountCreateCtx(context.Background(), data)
+}
+
+// AccountCreateCtx is like AccountCreate, but aborts when ctx is cancelled.
+func (s *Session) AccountCreateCtx(ctx context.Context, data AccountCreateParams) error {
 	endpoint := EndpointAuthAccount("create")
-	return s.HTTP.Request(http.MethodPost, endpoint, data, nil)
+	return s.HTTP.RequestContext(ctx, http.MethodPost, endpoint, data, nil)
 }
 
 func (s *Session) AccountReverify(data AccountReverifyParams) error {
+	return s.AccountReverifyCtx(context.Background(), data)
+}
+
+// AccountReverifyCtx is like AccountReverify, but aborts when ctx is cancelled.
+func (s *Session) AccountReverifyCtx(ctx context.Context, data AccountReverifyParams) error {
 	endpoint := EndpointAuthAccount("reverify")
-	return s.HTTP.Request(http.MethodPost, endpoint, data, nil)
+	return s.HTTP.RequestContext(ctx, http.MethodPost, endpoint, data, nil)
 }
 
 func (s *Session) AccountDeleteConfirm(data AccountDeleteConfirmParams) error {
+	return s.AccountDeleteConfirmCtx(context.Background(), data)
+}
+
+// AccountDeleteConfirmCtx is like AccountDeleteConfirm, but aborts when ctx is cancelled.
+func (s *Session) AccountDeleteConfirmCtx(ctx context.Context, data AccountDeleteConfirmParams) error {
 	endpoint := EndpointAuthAccount("delete")
-	return s.HTTP.Request(http.MethodPut, endpoint, data, nil)
+	return s.HTTP.RequestContext(ctx, http.MethodPut, endpoint, data, nil)
 }
 
 func (s *Session) AccountDelete() error {
+	return s.AccountDeleteCtx(context.Background())
+}
+
+// AccountDeleteCtx is like AccountDelete, but aborts when ctx is cancelled.
+func (s *Session) AccountDeleteCtx(ctx context.Context) error {
 	endpoint := EndpointAuthAccount("delete")
-	return s.HTTP.Request(http.MethodPost, endpoint, nil, nil)
+	return s.HTTP.RequestContext(ctx, http.MethodPost, endpoint, nil, nil)
 }
 
 func (s *Session) Account() (account *Account, err error) {
+	return s.AccountCtx(context.Background())
+}
+
+// AccountCtx is like Account, but aborts when ctx is cancelled.
+func (s *Session) AccountCtx(ctx context.Context) (account *Account, err error) {
 	endpoint := EndpointAuthAccount("")
-	err = s.HTTP.Request(http.MethodGet, endpoint, nil, &account)
+	err = s.HTTP.RequestContext(ctx, http.MethodGet, endpoint, nil, &account)
 	return
 }
 
 func (s *Session) AccountDisable() error {
+	return s.AccountDisableCtx(context.Background())
+}
+
+// AccountDisableCtx is like AccountDisable, but aborts when ctx is cancelled.
+func (s *Session) AccountDisableCtx(ctx context.Context) error {
 	endpoint := EndpointAuthAccount("disable")
-	return s.HTTP.Request(http.MethodPost, endpoint, nil, nil)
+	return s.HTTP.RequestContext(ctx, http.MethodPost, endpoint, nil, nil)
 }
 
 func (s *Session) AccountChangePassword(data AccountChangePasswordParams) error {
+	return s.AccountChangePasswordCtx(context.Background(), data)
+}
+
+// AccountChangePasswordCtx is like AccountChangePassword, but aborts when ctx is cancelled.
+func (s *Session) AccountChangePasswordCtx(ctx context.Context, data AccountChangePasswordParams) error {
 	endpoint := EndpointAuthAccountChange("password")
-	return s.HTTP.Request(http.MethodPatch, endpoint, data, nil)
+	return s.HTTP.RequestContext(ctx, http.MethodPatch, endpoint, data, nil)
 }
 
 func (s *Session) AccountChangeEmail(data AccountChangeEmailParams) error {
+	return s.AccountChangeEmailCtx(context.Background(), data)
+}
+
+// AccountChangeEmailCtx is like AccountChangeEmail, but aborts when ctx is cancelled.
+func (s *Session) AccountChangeEmailCtx(ctx context.Context, data AccountChangeEmailParams) error {
 	endpoint := EndpointAuthAccountChange("email")
-	return s.HTTP.Request(http.MethodPatch, endpoint, data, nil)
+	return s.HTTP.RequestContext(ctx, http.MethodPatch, endpoint, data, nil)
 }
 
 func (s *Session) VerifyEmail(code string) (ticket ChangeEmail, err error) {
+	return s.VerifyEmailCtx(context.Background(), code)
+}
+
+// VerifyEmailCtx is like VerifyEmail, but aborts when ctx is cancelled.
+func (s *Session) VerifyEmailCtx(ctx context.Context, code string) (ticket ChangeEmail, err error) {
 	endpoint := EndpointAuthAccountVerify(code)
-	err = s.HTTP.Request(http.MethodPost, endpoint, nil, &ticket)
+	err = s.HTTP.RequestContext(ctx, http.MethodPost, endpoint, nil, &ticket)
 	return
 }
 
 // PasswordReset requests a password reset, which is sent to the email provided
 func (s *Session) PasswordReset(data AccountReverifyParams) error {
+	return s.PasswordResetCtx(context.Background(), data)
+}
+
+// PasswordResetCtx is like PasswordReset, but aborts when ctx is cancelled.
+func (s *Session) PasswordResetCtx(ctx context.Context, data AccountReverifyParams) error {
 	endpoint := EndpointAuthAccount("reset_password")
-	return s.HTTP.Request(http.MethodPost, endpoint, data, nil)
+	return s.HTTP.RequestContext(ctx, http.MethodPost, endpoint, data, nil)
 }
 
 // PasswordResetConfirm confirms a password reset
 func (s *Session) PasswordResetConfirm(data PasswordResetConfirmParams) error {
+	return s.PasswordResetConfirmCtx(context.Background(), data)
+}
+
+// PasswordResetConfirmCtx is like PasswordResetConfirm, but aborts when ctx is cancelled.
+func (s *Session) PasswordResetConfirmCtx(ctx context.Context, data PasswordResetConfirmParams) error {
 	endpoint := EndpointAuthAccount("reset_password")
-	return s.HTTP.Request(http.MethodPatch, endpoint, data, nil)
+	return s.HTTP.RequestContext(ctx, http.MethodPatch, endpoint, data, nil)
 }
 
 // Login as a regular user instead of bot. Friendly name is used to identify the session via MFA
 func (s *Session) Login(data LoginParams) (mfa LoginResponse, err error) {
+	return s.LoginCtx(context.Background(), data)
+}
+
+// LoginCtx is like Login, but aborts when ctx is cancelled.
+func (s *Session) LoginCtx(ctx context.Context, data LoginParams) (mfa LoginResponse, err error) {
 	endpoint := EndpointAuthSession("login")
-	err = s.HTTP.Request(http.MethodPost, endpoint, data, &mfa)
+	err = s.HTTP.RequestContext(ctx, http.MethodPost, endpoint, data, &mfa)
 	return
 }
 
 func (s *Session) Sessions() (sessions []*Sessions, err error) {
+	return s.SessionsCtx(context.Background())
+}
+
+// SessionsCtx is like Sessions, but aborts when ctx is cancelled.
+func (s *Session) SessionsCtx(ctx context.Context) (sessions []*Sessions, err error) {
 	endpoint := EndpointAuthSession("all")
-	err = s.HTTP.Request(http.MethodGet, endpoint, nil, &sessions)
+	err = s.HTTP.RequestContext(ctx, http.MethodGet, endpoint, nil, &sessions)
 	return
 }
 
 func (s *Session) SessionEdit(id string, data SessionEditParams) (session SessionEditParams, err error) {
+	return s.SessionEditCtx(context.Background(), id, data)
+}
+
+// SessionEditCtx is like SessionEdit, but aborts when ctx is cancelled.
+func (s *Session) SessionEditCtx(ctx context.Context, id string, data SessionEditParams) (session SessionEditParams, err error) {
 	endpoint := EndpointAuthSession(id)
-	err = s.HTTP.Request(http.MethodPatch, endpoint, data, &session)
+	err = s.HTTP.RequestContext(ctx, http.MethodPatch, endpoint, data, &session)
 	return
 }
 
 // Onboarding returns whether the current account requires onboarding or whether you can continue to send requests as usual
 func (s *Session) Onboarding() (onboarding Onboarding, err error) {
+	return s.OnboardingCtx(context.Background())
+}
+
+// OnboardingCtx is like Onboarding, but aborts when ctx is cancelled.
+func (s *Session) OnboardingCtx(ctx context.Context) (onboarding Onboarding, err error) {
 	endpoint := EndpointOnboard("hello")
-	err = s.HTTP.Request(http.MethodGet, endpoint, nil, &onboarding)
+	err = s.HTTP.RequestContext(ctx, http.MethodGet, endpoint, nil, &onboarding)
 	return
 }
 
 // OnboardingComplete sets a new username, completes onboarding and allows a user to start using Revolt.
 func (s *Session) OnboardingComplete(data OnboardingCompleteParams) error {
+	return s.OnboardingCompleteCtx(context.Background(), data)
+}
+
+// OnboardingCompleteCtx is like OnboardingComplete, but aborts when ctx is cancelled.
+func (s *Session) OnboardingCompleteCtx(ctx context.Context, data OnboardingCompleteParams) error {
 	endpoint := EndpointOnboard("complete")
-	return s.HTTP.Request(http.MethodPost, endpoint, data, nil)
+	return s.HTTP.RequestContext(ctx, http.MethodPost, endpoint, data, nil)
 }
 
 // SessionsDelete invalidates a session with the provided ID
 func (s *Session) SessionsDelete(id string) error {
+	return s.SessionsDeleteCtx(context.Background(), id)
+}
+
+// SessionsDeleteCtx is like SessionsDelete, but aborts when ctx is cancelled.
+func (s *Session) SessionsDeleteCtx(ctx context.Context, id string) error {
 	endpoint := EndpointAuthSession(id)
-	return s.HTTP.Request(http.MethodDelete, endpoint, nil, nil)
+	return s.HTTP.RequestContext(ctx, http.MethodDelete, endpoint, nil, nil)
 }
 
 // SessionsDeleteAll invalidates all sessions, including this one if revokeSelf is true
 func (s *Session) SessionsDeleteAll(revokeSelf bool) error {
+	return s.SessionsDeleteAllCtx(context.Background(), revokeSelf)
+}
+
+// SessionsDeleteAllCtx is like SessionsDeleteAll, but aborts when ctx is cancelled.
+func (s *Session) SessionsDeleteAllCtx(ctx context.Context, revokeSelf bool) error {
 	endpoint := EndpointAuthSession("all")
 	if revokeSelf {
 		values := url.Values{}
@@ -1139,24 +1920,39 @@ func (s *Session) SessionsDeleteAll(revokeSelf bool) error {
 		endpoint += fmt.Sprintf("?%s", values.Encode())
 	}
 
-	return s.HTTP.Request(http.MethodDelete, endpoint, nil, nil)
+	return s.HTTP.RequestContext(ctx, http.MethodDelete, endpoint, nil, nil)
 }
 
 func (s *Session) Logout() error {
+	return s.LogoutCtx(context.Background())
+}
+
+// LogoutCtx is like Logout, but aborts when ctx is cancelled.
+func (s *Session) LogoutCtx(ctx context.Context) error {
 	endpoint := EndpointAuthSession("logout")
-	return s.HTTP.Request(http.MethodPost, endpoint, nil, nil)
+	return s.HTTP.RequestContext(ctx, http.MethodPost, endpoint, nil, nil)
 }
 
 func (s *Session) UserMutual(uID string) (mutual []*MutualFriendsAndServersResponse, err error) {
+	return s.UserMutualCtx(context.Background(), uID)
+}
+
+// UserMutualCtx is like UserMutual, but aborts when ctx is cancelled.
+func (s *Session) UserMutualCtx(ctx context.Context, uID string) (mutual []*MutualFriendsAndServersResponse, err error) {
 	endpoint := EndpointUserMutual(uID)
-	err = s.HTTP.Request(http.MethodGet, endpoint, nil, &mutual)
+	err = s.HTTP.RequestContext(ctx, http.MethodGet, endpoint, nil, &mutual)
 	return
 }
 
 // DirectMessages returns a list of direct message channels.
 func (s *Session) DirectMessages() (channels []*Channel, err error) {
+	return s.DirectMessagesCtx(context.Background())
+}
+
+// DirectMessagesCtx is like DirectMessages, but aborts when ctx is cancelled.
+func (s *Session) DirectMessagesCtx(ctx context.Context) (channels []*Channel, err error) {
 	endpoint := EndpointUser("dms")
-	err = s.HTTP.Request(http.MethodGet, endpoint, nil, &channels)
+	err = s.HTTP.RequestContext(ctx, http.MethodGet, endpoint, nil, &channels)
 	if err == nil {
 		s.State.addChannels(channels)
 	}
@@ -1166,8 +1962,13 @@ func (s *Session) DirectMessages() (channels []*Channel, err error) {
 // DirectMessageCreate opens a direct message channel with a user
 // Will return an error "MissingPermission" "SendMessage" if you are not friends or blocked
 func (s *Session) DirectMessageCreate(uID string) (channel *Channel, err error) {
+	return s.DirectMessageCreateCtx(context.Background(), uID)
+}
+
+// DirectMessageCreateCtx is like DirectMessageCreate, but aborts when ctx is cancelled.
+func (s *Session) DirectMessageCreateCtx(ctx context.Context, uID string) (channel *Channel, err error) {
 	endpoint := EndpointUserDM(uID)
-	err = s.HTTP.Request(http.MethodGet, endpoint, nil, &channel)
+	err = s.HTTP.RequestContext(ctx, http.MethodGet, endpoint, nil, &channel)
 	return
 }
 
@@ -1175,138 +1976,253 @@ func (s *Session) DirectMessageCreate(uID string) (channel *Channel, err error)
 // todo: this might be completely wrong, check: https://developers.stoat.chat/api-reference#tag/relationships/POST/users/friend
 // todo: this POSTS to /user/friend with body "username" (Username and discriminator combo separated by #)
 func (s *Session) FriendAdd(uID string) (user *User, err error) {
+	return s.FriendAddCtx(context.Background(), uID)
+}
+
+// FriendAddCtx is like FriendAdd, but aborts when ctx is cancelled.
+func (s *Session) FriendAddCtx(ctx context.Context, uID string) (user *User, err error) {
 	endpoint := EndpointUserFriend(uID)
-	err = s.HTTP.Request(http.MethodPut, endpoint, nil, &user)
+	err = s.HTTP.RequestContext(ctx, http.MethodPut, endpoint, nil, &user)
 	return
 }
 
 // FriendDelete removes a friend or declines a friend request.
 func (s *Session) FriendDelete(uID string) (user *User, err error) {
+	return s.FriendDeleteCtx(context.Background(), uID)
+}
+
+// FriendDeleteCtx is like FriendDelete, but aborts when ctx is cancelled.
+func (s *Session) FriendDeleteCtx(ctx context.Context, uID string) (user *User, err error) {
 	endpoint := EndpointUserFriend(uID)
-	err = s.HTTP.Request(http.MethodDelete, endpoint, nil, &user)
+	err = s.HTTP.RequestContext(ctx, http.MethodDelete, endpoint, nil, &user)
 	return
 }
 
 // Bot fetches details of a bot you own by its ID
 func (s *Session) Bot(bID string) (bot *FetchedBot, err error) {
+	return s.BotCtx(context.Background(), bID)
+}
+
+// BotCtx is like Bot, but aborts when ctx is cancelled.
+func (s *Session) BotCtx(ctx context.Context, bID string) (bot *FetchedBot, err error) {
 	endpoint := EndpointBot(bID)
-	err = s.HTTP.Request(http.MethodGet, endpoint, nil, &bot)
+	err = s.HTTP.RequestContext(ctx, http.MethodGet, endpoint, nil, &bot)
 	return
 }
 
 // Bots returns a list of bots for the current user
 func (s *Session) Bots() (bots *FetchedBots, err error) {
+	return s.BotsCtx(context.Background())
+}
+
+// BotsCtx is like Bots, but aborts when ctx is cancelled.
+func (s *Session) BotsCtx(ctx context.Context) (bots *FetchedBots, err error) {
 	endpoint := EndpointBot("@me")
-	err = s.HTTP.Request(http.MethodGet, endpoint, nil, &bots)
+	err = s.HTTP.RequestContext(ctx, http.MethodGet, endpoint, nil, &bots)
 	return
 }
 
 // BotCreate creates a bot based on the data provided
 func (s *Session) BotCreate(data BotCreateParams) (bot *Bot, err error) {
+	return s.BotCreateCtx(context.Background(), data)
+}
+
+// BotCreateCtx is like BotCreate, but aborts when ctx is cancelled.
+func (s *Session) BotCreateCtx(ctx context.Context, data BotCreateParams) (bot *Bot, err error) {
 	endpoint := EndpointBot("create")
-	err = s.HTTP.Request(http.MethodPost, endpoint, data, &bot)
+	err = s.HTTP.RequestContext(ctx, http.MethodPost, endpoint, data, &bot)
 	return
 }
 
 func (s *Session) BotEdit(id string, data BotEditParams) (bot *Bot, err error) {
+	return s.BotEditCtx(context.Background(), id, data)
+}
+
+// BotEditCtx is like BotEdit, but aborts when ctx is cancelled.
+func (s *Session) BotEditCtx(ctx context.Context, id string, data BotEditParams) (bot *Bot, err error) {
 	endpoint := EndpointBot(id)
-	err = s.HTTP.Request(http.MethodPatch, endpoint, data, &bot)
+	err = s.HTTP.RequestContext(ctx, http.MethodPatch, endpoint, data, &bot)
 	return
 }
 
 func (s *Session) BotDelete(bID string) error {
+	return s.BotDeleteCtx(context.Background(), bID)
+}
+
+// BotDeleteCtx is like BotDelete, but aborts when ctx is cancelled.
+func (s *Session) BotDeleteCtx(ctx context.Context, bID string) error {
 	endpoint := EndpointBot(bID)
-	return s.HTTP.Request(http.MethodDelete, endpoint, nil, nil)
+	return s.HTTP.RequestContext(ctx, http.MethodDelete, endpoint, nil, nil)
 }
 
 // BotPublic fetches a public bot by its ID
 func (s *Session) BotPublic(bID string) (bot *PublicBot, err error) {
+	return s.BotPublicCtx(context.Background(), bID)
+}
+
+// BotPublicCtx is like BotPublic, but aborts when ctx is cancelled.
+func (s *Session) BotPublicCtx(ctx context.Context, bID string) (bot *PublicBot, err error) {
 	endpoint := EndpointBotInvite(bID)
-	err = s.HTTP.Request(http.MethodGet, endpoint, nil, &bot)
+	err = s.HTTP.RequestContext(ctx, http.MethodGet, endpoint, nil, &bot)
 	return
 }
 
 // BotInvite invites a bot by its ID to a server or group
 func (s *Session) BotInvite(bID string, data BotInviteParams) (err error) {
+	return s.BotInviteCtx(context.Background(), bID, data)
+}
+
+// BotInviteCtx is like BotInvite, but aborts when ctx is cancelled.
+func (s *Session) BotInviteCtx(ctx context.Context, bID string, data BotInviteParams) (err error) {
 	endpoint := EndpointBotInvite(bID)
-	err = s.HTTP.Request(http.MethodPost, endpoint, data, nil)
+	err = s.HTTP.RequestContext(ctx, http.MethodPost, endpoint, data, nil)
 	return
 }
 
 func (s *Session) SyncUnreads() (data []ChannelUnread, err error) {
+	return s.SyncUnreadsCtx(context.Background())
+}
+
+// SyncUnreadsCtx is like SyncUnreads, but aborts when ctx is cancelled.
+func (s *Session) SyncUnreadsCtx(ctx context.Context) (data []ChannelUnread, err error) {
 	endpoint := EndpointSync("unreads")
-	err = s.HTTP.Request(http.MethodGet, endpoint, nil, &data)
+	err = s.HTTP.RequestContext(ctx, http.MethodGet, endpoint, nil, &data)
 	return
 }
 
 func (s *Session) SyncSettingsFetch(payload SyncSettingsFetchParams) (data *SyncSettingsParams, err error) {
+	return s.SyncSettingsFetchCtx(context.Background(), payload)
+}
+
+// SyncSettingsFetchCtx is like SyncSettingsFetch, but aborts when ctx is cancelled.
+func (s *Session) SyncSettingsFetchCtx(ctx context.Context, payload SyncSettingsFetchParams) (data *SyncSettingsParams, err error) {
 	endpoint := EndpointSyncSettings("fetch")
-	err = s.HTTP.Request(http.MethodPost, endpoint, payload, &data)
+	err = s.HTTP.RequestContext(ctx, http.MethodPost, endpoint, payload, &data)
 	return
 }
 
 func (s *Session) SyncSettingsSet(payload SyncSettingsParams) error {
+	return s.SyncSettingsSetCtx(context.Background(), payload)
+}
+
+// SyncSettingsSetCtx is like SyncSettingsSet, but aborts when ctx is cancelled.
+func (s *Session) SyncSettingsSetCtx(ctx context.Context, payload SyncSettingsParams) error {
 	endpoint := EndpointSyncSettings("set")
-	return s.HTTP.Request(http.MethodPost, endpoint, payload, nil)
+	return s.HTTP.RequestContext(ctx, http.MethodPost, endpoint, payload, nil)
 }
 
 func (s *Session) PushSubscribe(data WebpushSubscription) error {
+	return s.PushSubscribeCtx(context.Background(), data)
+}
+
+// PushSubscribeCtx is like PushSubscribe, but aborts when ctx is cancelled.
+func (s *Session) PushSubscribeCtx(ctx context.Context, data WebpushSubscription) error {
 	endpoint := EndpointPush("subscribe")
-	return s.HTTP.Request(http.MethodPost, endpoint, data, nil)
+	return s.HTTP.RequestContext(ctx, http.MethodPost, endpoint, data, nil)
 }
 
 func (s *Session) PushUnsubscribe(data WebpushSubscription) error {
+	return s.PushUnsubscribeCtx(context.Background(), data)
+}
+
+// PushUnsubscribeCtx is like PushUnsubscribe, but aborts when ctx is cancelled.
+func (s *Session) PushUnsubscribeCtx(ctx context.Context, data WebpushSubscription) error {
 	endpoint := EndpointPush("unsubscribe")
-	return s.HTTP.Request(http.MethodPost, endpoint, data, nil)
+	return s.HTTP.RequestContext(ctx, http.MethodPost, endpoint, data, nil)
 }
 
 func (s *Session) AuthMFA() (mfa AuthMFAResponse, err error) {
+	return s.AuthMFACtx(context.Background())
+}
+
+// AuthMFACtx is like AuthMFA, but aborts when ctx is cancelled.
+func (s *Session) AuthMFACtx(ctx context.Context) (mfa AuthMFAResponse, err error) {
 	endpoint := EndpointAuthMFA("")
-	err = s.HTTP.Request(http.MethodGet, endpoint, nil, &mfa)
+	err = s.HTTP.RequestContext(ctx, http.MethodGet, endpoint, nil, &mfa)
 	return
 }
 
 func (s *Session) AuthMFACreateTicket(data AuthMFAParams) (ticket AuthMFATicketResponse, err error) {
+	return s.AuthMFACreateTicketCtx(context.Background(), data)
+}
+
+// AuthMFACreateTicketCtx is like AuthMFACreateTicket, but aborts when ctx is cancelled.
+func (s *Session) AuthMFACreateTicketCtx(ctx context.Context, data AuthMFAParams) (ticket AuthMFATicketResponse, err error) {
 	endpoint := EndpointAuthMFA("ticket")
-	err = s.HTTP.Request(http.MethodPut, endpoint, data, &ticket)
+	err = s.HTTP.RequestContext(ctx, http.MethodPut, endpoint, data, &ticket)
 	return
 }
 
 func (s *Session) AuthMFARecoveryCodes() (codes []string, err error) {
+	return s.AuthMFARecoveryCodesCtx(context.Background())
+}
+
+// AuthMFARecoveryCodesCtx is like AuthMFARecoveryCodes, but aborts when ctx is cancelled.
+func (s *Session) AuthMFARecoveryCodesCtx(ctx context.Context) (codes []string, err error) {
 	endpoint := EndpointAuthMFA("recovery")
-	err = s.HTTP.Request(http.MethodPost, endpoint, nil, &codes)
+	err = s.HTTP.RequestContext(ctx, http.MethodPost, endpoint, nil, &codes)
 	return
 }
 
 func (s *Session) AuthMFAGenerateRecoveryCodes() (codes []string, err error) {
+	return s.AuthMFAGenerateRecoveryCodesCtx(context.Background())
+}
+
+// AuthMFAGenerateRecoveryCodesCtx is like AuthMFAGenerateRecoveryCodes, but aborts when ctx is cancelled.
+func (s *Session) AuthMFAGenerateRecoveryCodesCtx(ctx context.Context) (codes []string, err error) {
 	endpoint := EndpointAuthMFA("recovery")
-	err = s.HTTP.Request(http.MethodPatch, endpoint, nil, &codes)
+	err = s.HTTP.RequestContext(ctx, http.MethodPatch, endpoint, nil, &codes)
 	return
 }
 
 func (s *Session) AuthMFAMethods() (methods []AuthMFAMethod, err error) {
+	return s.AuthMFAMethodsCtx(context.Background())
+}
+
+// AuthMFAMethodsCtx is like AuthMFAMethods, but aborts when ctx is cancelled.
+func (s *Session) AuthMFAMethodsCtx(ctx context.Context) (methods []AuthMFAMethod, err error) {
 	endpoint := EndpointAuthMFA("methods")
-	err = s.HTTP.Request(http.MethodGet, endpoint, nil, &methods)
+	err = s.HTTP.RequestContext(ctx, http.MethodGet, endpoint, nil, &methods)
 	return
 }
 
 func (s *Session) AuthMFAEnable2FATOTP(data AuthMFAParams) (err error) {
+	return s.AuthMFAEnable2FATOTPCtx(context.Background(), data)
+}
+
+// AuthMFAEnable2FATOTPCtx is like AuthMFAEnable2FATOTP, but aborts when ctx is cancelled.
+func (s *Session) AuthMFAEnable2FATOTPCtx(ctx context.Context, data AuthMFAParams) (err error) {
 	endpoint := EndpointAuthMFA("totp")
-	return s.HTTP.Request(http.MethodPut, endpoint, data, nil)
+	return s.HTTP.RequestContext(ctx, http.MethodPut, endpoint, data, nil)
 }
 
 func (s *Session) AuthMFADisable2FATOTP() (err error) {
+	return s.AuthMFADisable2FATOTPCtx(context.Background())
+}
+
+// AuthMFADisable2FATOTPCtx is like AuthMFADisable2FATOTP, but aborts when ctx is cancelled.
+func (s *Session) AuthMFADisable2FATOTPCtx(ctx context.Context) (err error) {
 	endpoint := EndpointAuthMFA("totp")
-	return s.HTTP.Request(http.MethodDelete, endpoint, nil, nil)
+	return s.HTTP.RequestContext(ctx, http.MethodDelete, endpoint, nil, nil)
 }
 
 func (s *Session) AuthMFAGenerateTOTPSecret() (secret AuthMFATOTPSecretResponse, err error) {
+	return s.AuthMFAGenerateTOTPSecretCtx(context.Background())
+}
+
+// AuthMFAGenerateTOTPSecretCtx is like AuthMFAGenerateTOTPSecret, but aborts when ctx is cancelled.
+func (s *Session) AuthMFAGenerateTOTPSecretCtx(ctx context.Context) (secret AuthMFATOTPSecretResponse, err error) {
 	endpoint := EndpointAuthMFA("totp")
-	err = s.HTTP.Request(http.MethodPost, endpoint, nil, &secret)
+	err = s.HTTP.RequestContext(ctx, http.MethodPost, endpoint, nil, &secret)
 	return
 }
 
 func (s *Session) PolicyAck() (err error) {
+	return s.PolicyAckCtx(context.Background())
+}
+
+// PolicyAckCtx is like PolicyAck, but aborts when ctx is cancelled.
+func (s *Session) PolicyAckCtx(ctx context.Context) (err error) {
 	endpoint := EndpointPolicy("acknowledge")
-	return s.HTTP.Request(http.MethodPost, endpoint, nil, nil)
+	return s.HTTP.RequestContext(ctx, http.MethodPost, endpoint, nil, nil)
 }
@@ -0,0 +1,91 @@
+package revoltgo
+
+// Snapshot events are dispatched after the State has applied an update or delete event. They carry the original
+// event, the cached object as it was Before the event, and for updates, the cached object After it.
+// Before is nil if the object wasn't cached, and After is nil if it still isn't (e.g. the server is unknown).
+//
+// They are only dispatched for the caches enabled in StateConfig, and are registered like any other event:
+//
+//	revoltgo.AddHandler(session, func(s *revoltgo.Session, e *revoltgo.EventServerUpdateSnapshot) {
+//		if e.Before != nil && e.Before.Name != e.After.Name {
+//			// the server was renamed
+//		}
+//	})
+//
+// Before is a shallow copy: nested maps and slices (e.g. Server.Roles) may still be shared with the cache,
+// so treat it as read-only and don't hold on to it expecting it to stay unchanged.
+
+type EventServerUpdateSnapshot struct {
+	*EventServerUpdate
+	Before *Server
+	After  *Server
+}
+
+type EventServerDeleteSnapshot struct {
+	*EventServerDelete
+	Before *Server
+}
+
+// EventServerRoleUpdateSnapshot has a nil Before when the role was created.
+type EventServerRoleUpdateSnapshot struct {
+	*EventServerRoleUpdate
+	Before *ServerRole
+	After  *ServerRole
+}
+
+type EventServerRoleDeleteSnapshot struct {
+	*EventServerRoleDelete
+	Before *ServerRole
+}
+
+type EventServerMemberUpdateSnapshot struct {
+	*EventServerMemberUpdate
+	Before *ServerMember
+	After  *ServerMember
+}
+
+type EventServerMemberLeaveSnapshot struct {
+	*EventServerMemberLeave
+	Before *ServerMember
+}
+
+type EventChannelUpdateSnapshot struct {
+	*EventChannelUpdate
+	Before *Channel
+	After  *Channel
+}
+
+type EventChannelDeleteSnapshot struct {
+	*EventChannelDelete
+	Before *Channel
+}
+
+type EventUserUpdateSnapshot struct {
+	*EventUserUpdate
+	Before *User
+	After  *User
+}
+
+type EventEmojiDeleteSnapshot struct {
+	*EventEmojiDelete
+	Before *Emoji
+}
+
+// EventMessageUpdateSnapshot requires StateConfig.TrackMessages.
+type EventMessageUpdateSnapshot struct {
+	*EventMessageUpdate
+	Before *Message
+	After  *Message
+}
+
+// EventMessageDeleteSnapshot requires StateConfig.TrackMessages.
+type EventMessageDeleteSnapshot struct {
+	*EventMessageDelete
+	Before *Message
+}
+
+// EventBulkMessageDeleteSnapshot requires StateConfig.TrackMessages. Before only holds the messages that were cached.
+type EventBulkMessageDeleteSnapshot struct {
+	*EventBulkMessageDelete
+	Before []*Message
+}
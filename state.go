@@ -2,7 +2,7 @@ package revoltgo
 
 import (
 	"iter"
-	"log"
+	"log/slog"
 	"slices"
 	"sync"
 	"sync/atomic"
@@ -102,6 +102,199 @@ func (sm stateMembers) upsert(id MemberCompositeID) *ServerMember {
 	return member
 }
 
+// defaultMessagesPerChannel is used when StateConfig.MessagesPerChannel is not set
+const defaultMessagesPerChannel = 100
+
+// channelMessages holds the newest messages of a channel.
+// Message IDs are ULIDs, so sorting the IDs sorts the messages by creation time.
+type channelMessages struct {
+	ids      []string            // Message.ID's, oldest first
+	messages map[string]*Message // Message.ID -> Message
+}
+
+// stateMessages maps a Channel.ID -> [ Message.ID -> Message ]
+type stateMessages map[string]*channelMessages
+
+// add adds or replaces a message, evicting the oldest messages of its channel beyond capacity.
+func (sm stateMessages) add(message *Message, capacity int) {
+	channel := sm[message.Channel]
+	if channel == nil {
+		channel = &channelMessages{messages: make(map[string]*Message)}
+		sm[message.Channel] = channel
+	}
+
+	if _, exists := channel.messages[message.ID]; !exists {
+		index, _ := slices.BinarySearch(channel.ids, message.ID)
+		channel.ids = slices.Insert(channel.ids, index, message.ID)
+	}
+
+	channel.messages[message.ID] = message
+
+	if overflow := len(channel.ids) - capacity; overflow > 0 {
+		for _, mID := range channel.ids[:overflow] {
+			delete(channel.messages, mID)
+		}
+
+		channel.ids = slices.Delete(channel.ids, 0, overflow)
+	}
+}
+
+// get returns a cached message, or nil if the channel or message is not cached.
+func (sm stateMessages) get(cID, mID string) *Message {
+	channel := sm[cID]
+	if channel == nil {
+		return nil
+	}
+
+	return channel.messages[mID]
+}
+
+// remove drops a single message from a channel.
+func (sm stateMessages) remove(cID, mID string) {
+	channel := sm[cID]
+	if channel == nil {
+		return
+	}
+
+	if _, exists := channel.messages[mID]; !exists {
+		return
+	}
+
+	delete(channel.messages, mID)
+	if index, found := slices.BinarySearch(channel.ids, mID); found {
+		channel.ids = slices.Delete(channel.ids, index, index+1)
+	}
+}
+
+// removeChannel drops a channel's entire message cache.
+func (sm stateMessages) removeChannel(cID string) {
+	delete(sm, cID)
+}
+
+// stateVoice tracks who is in which voice channel, from both directions
+type stateVoice struct {
+	channels map[string]map[string]*UserVoiceState // Channel.ID -> [ User.ID -> UserVoiceState ]
+	users    map[string]string                     // User.ID    -> Channel.ID
+}
+
+func newStateVoice() stateVoice {
+	return stateVoice{
+		channels: make(map[string]map[string]*UserVoiceState),
+		users:    make(map[string]string),
+	}
+}
+
+// join adds a participant to a channel, removing them from any channel we missed them leave.
+func (sv stateVoice) join(cID string, state *UserVoiceState) {
+	if previous, exists := sv.users[state.ID]; exists && previous != cID {
+		sv.leave(previous, state.ID)
+	}
+
+	participants := sv.channels[cID]
+	if participants == nil {
+		participants = make(map[string]*UserVoiceState)
+		sv.channels[cID] = participants
+	}
+
+	participants[state.ID] = state
+	sv.users[state.ID] = cID
+}
+
+// leave removes a participant from a channel, dropping the channel once it is empty.
+func (sv stateVoice) leave(cID, uID string) {
+	participants := sv.channels[cID]
+	delete(participants, uID)
+
+	if len(participants) == 0 {
+		delete(sv.channels, cID)
+	}
+
+	if sv.users[uID] == cID {
+		delete(sv.users, uID)
+	}
+}
+
+// removeChannel drops a channel and all of its participants.
+func (sv stateVoice) removeChannel(cID string) {
+	for uID := range sv.channels[cID] {
+		delete(sv.users, uID)
+	}
+
+	delete(sv.channels, cID)
+}
+
+// stateUnreads tracks what the current user has read
+type stateUnreads struct {
+	read   map[string]*ChannelUnread // Channel.ID -> last read message and unread mentions
+	latest map[string]string         // Channel.ID -> newest message ID by someone else
+}
+
+func newStateUnreads() stateUnreads {
+	return stateUnreads{
+		read:   make(map[string]*ChannelUnread),
+		latest: make(map[string]string),
+	}
+}
+
+// Entries in read are replaced rather than edited, so a *ChannelUnread handed out by a getter never changes.
+
+// ack moves the last read message of a channel, clearing the mentions up to it.
+func (su stateUnreads) ack(cID, uID, mID string) {
+	next := &ChannelUnread{
+		ID:            ChannelUnreadCompositeID{Channel: cID, User: uID},
+		LastMessageID: &mID,
+	}
+
+	if previous := su.read[cID]; previous != nil {
+		for _, mentionID := range previous.MentionIDs {
+			if mentionID > mID {
+				next.MentionIDs = append(next.MentionIDs, mentionID)
+			}
+		}
+	}
+
+	su.read[cID] = next
+}
+
+// mention records a message that mentions the current user.
+func (su stateUnreads) mention(cID, uID, mID string) {
+	next := &ChannelUnread{ID: ChannelUnreadCompositeID{Channel: cID, User: uID}}
+
+	if previous := su.read[cID]; previous != nil {
+		if slices.Contains(previous.MentionIDs, mID) {
+			return
+		}
+
+		next.LastMessageID = previous.LastMessageID
+		next.MentionIDs = slices.Clone(previous.MentionIDs)
+	}
+
+	next.MentionIDs = append(next.MentionIDs, mID)
+	su.read[cID] = next
+}
+
+// unread reports whether a channel has messages newer than the last read one, or unread mentions.
+// Message IDs are ULIDs, so comparing them compares their creation time.
+func (su stateUnreads) unread(cID string) bool {
+	read := su.read[cID]
+	if read != nil && len(read.MentionIDs) > 0 {
+		return true
+	}
+
+	latest, exists := su.latest[cID]
+	if !exists {
+		return false
+	}
+
+	return read == nil || read.LastMessageID == nil || latest > *read.LastMessageID
+}
+
+// removeChannel forgets a channel.
+func (su stateUnreads) removeChannel(cID string) {
+	delete(su.read, cID)
+	delete(su.latest, cID)
+}
+
 type State struct {
 	self atomic.Pointer[User] // The current user, also present in users
 
@@ -111,6 +304,9 @@ type State struct {
 	channels map[string]*Channel // Channel.ID -> Channel
 	emojis   map[string]*Emoji   // Emoji.ID   -> Emoji.
 	members  stateMembers        // Server.ID  -> [ User.ID -> Member.ID ]
+	messages stateMessages       // Channel.ID -> [ Message.ID -> Message ]
+	voice    stateVoice          // Channel.ID <-> [ User.ID -> UserVoiceState ]
+	unreads  stateUnreads        // Channel.ID -> ChannelUnread
 
 	/* Mutexes for caches */
 	usersMu    sync.RWMutex
@@ -118,6 +314,9 @@ type State struct {
 	channelsMu sync.RWMutex
 	membersMu  sync.RWMutex
 	emojisMu   sync.RWMutex
+	messagesMu sync.RWMutex
+	voiceMu    sync.RWMutex
+	unreadsMu  sync.RWMutex
 
 	/* tracking options */
 
@@ -126,6 +325,12 @@ type State struct {
 	trackChannels bool
 	trackMembers  bool
 	trackEmojis   bool
+	trackMessages bool
+	trackVoice    bool
+	trackUnreads  bool
+
+	// messagesPerChannel caps how many messages are cached per channel
+	messagesPerChannel int
 
 	// trackAPICalls additionally updates the state from API calls
 	// This concept may future-proof against any de-syncs, but may use more CPU time
@@ -134,6 +339,9 @@ type State struct {
 	// trackBulkAPICalls will update the state from bulk API calls
 	// This option activates internal State.addServerMembersAndUsers methods
 	trackBulkAPICalls bool
+
+	// logger is the owning Session's logger
+	logger func() *slog.Logger
 }
 
 /*
@@ -169,6 +377,18 @@ func (s *State) TrackEmojis() bool {
 	return s.trackEmojis
 }
 
+func (s *State) TrackMessages() bool {
+	return s.trackMessages
+}
+
+func (s *State) TrackVoiceStates() bool {
+	return s.trackVoice
+}
+
+func (s *State) TrackUnreads() bool {
+	return s.trackUnreads
+}
+
 func (s *State) TrackAPICalls() bool {
 	return s.trackAPICalls
 }
@@ -416,6 +636,181 @@ func (s *State) Emojis() []*Emoji {
 	return emojis
 }
 
+// Message returns a cached message, or nil if it was never seen or has been evicted.
+func (s *State) Message(cID, mID string) *Message {
+	s.messagesMu.RLock()
+	defer s.messagesMu.RUnlock()
+
+	return s.messages.get(cID, mID)
+}
+
+// MessageCount returns how many messages are cached for a channel.
+func (s *State) MessageCount(cID string) int {
+	s.messagesMu.RLock()
+	defer s.messagesMu.RUnlock()
+
+	channel := s.messages[cID]
+	if channel == nil {
+		return 0
+	}
+
+	return len(channel.ids)
+}
+
+// MessageSeq iterates a channel's cached messages, oldest first, without allocating a slice.
+// The same loop-body rules as MembersSeq apply: keep it quick and don't call other State methods
+// from inside it. Use Messages if you need a snapshot.
+func (s *State) MessageSeq(cID string) iter.Seq[*Message] {
+	return func(yield func(*Message) bool) {
+		s.messagesMu.RLock()
+		defer s.messagesMu.RUnlock()
+
+		channel := s.messages[cID]
+		if channel == nil {
+			return
+		}
+
+		for _, mID := range channel.ids {
+			if !yield(channel.messages[mID]) {
+				return
+			}
+		}
+	}
+}
+
+// Messages returns a snapshot slice of a channel's cached messages, oldest first.
+func (s *State) Messages(cID string) []*Message {
+	s.messagesMu.RLock()
+	defer s.messagesMu.RUnlock()
+
+	channel := s.messages[cID]
+	if channel == nil {
+		return nil
+	}
+
+	messages := make([]*Message, 0, len(channel.ids))
+	for _, mID := range channel.ids {
+		messages = append(messages, channel.messages[mID])
+	}
+
+	return messages
+}
+
+// VoiceState returns a snapshot of a voice channel's participants, or nil if nobody is connected.
+func (s *State) VoiceState(cID string) *ChannelVoiceState {
+	s.voiceMu.RLock()
+	defer s.voiceMu.RUnlock()
+
+	participants := s.voice.channels[cID]
+	if len(participants) == 0 {
+		return nil
+	}
+
+	state := &ChannelVoiceState{
+		ID:           cID,
+		Participants: make([]*UserVoiceState, 0, len(participants)),
+	}
+
+	for _, participant := range participants {
+		state.Participants = append(state.Participants, participant)
+	}
+
+	return state
+}
+
+// UserVoiceChannel returns the ID of the voice channel a user is connected to, or "" if none.
+func (s *State) UserVoiceChannel(uID string) string {
+	s.voiceMu.RLock()
+	defer s.voiceMu.RUnlock()
+
+	return s.voice.users[uID]
+}
+
+// VoiceParticipant returns a user's voice state in a channel, or nil if they are not connected to it.
+func (s *State) VoiceParticipant(cID, uID string) *UserVoiceState {
+	s.voiceMu.RLock()
+	defer s.voiceMu.RUnlock()
+
+	return s.voice.channels[cID][uID]
+}
+
+func (s *State) VoiceParticipantCount(cID string) int {
+	s.voiceMu.RLock()
+	defer s.voiceMu.RUnlock()
+
+	return len(s.voice.channels[cID])
+}
+
+// VoiceParticipantSeq iterates a voice channel's participants without allocating a slice.
+// The same loop-body rules as MembersSeq apply: keep it quick and don't call other State methods
+// from inside it. Use VoiceState if you need a snapshot.
+func (s *State) VoiceParticipantSeq(cID string) iter.Seq[*UserVoiceState] {
+	return func(yield func(*UserVoiceState) bool) {
+		s.voiceMu.RLock()
+		defer s.voiceMu.RUnlock()
+
+		for _, participant := range s.voice.channels[cID] {
+			if !yield(participant) {
+				return
+			}
+		}
+	}
+}
+
+// VoiceChannelSeq iterates the IDs of voice channels that have participants.
+// The same loop-body rules as MembersSeq apply.
+func (s *State) VoiceChannelSeq() iter.Seq[string] {
+	return func(yield func(string) bool) {
+		s.voiceMu.RLock()
+		defer s.voiceMu.RUnlock()
+
+		for cID := range s.voice.channels {
+			if !yield(cID) {
+				return
+			}
+		}
+	}
+}
+
+// Unread returns the last read message and unread mentions of a channel, or nil if it is unknown.
+// The returned value is never modified; a fresh one is stored whenever it changes.
+func (s *State) Unread(cID string) *ChannelUnread {
+	s.unreadsMu.RLock()
+	defer s.unreadsMu.RUnlock()
+
+	return s.unreads.read[cID]
+}
+
+// HasUnread reports whether a channel has messages newer than the last one read, or unread mentions.
+func (s *State) HasUnread(cID string) bool {
+	s.unreadsMu.RLock()
+	defer s.unreadsMu.RUnlock()
+
+	return s.unreads.unread(cID)
+}
+
+// UnreadChannels returns the IDs of every channel for which HasUnread is true.
+func (s *State) UnreadChannels() []string {
+	s.unreadsMu.RLock()
+	defer s.unreadsMu.RUnlock()
+
+	var channels []string
+	for cID := range s.unreads.latest {
+		if s.unreads.unread(cID) {
+			channels = append(channels, cID)
+		}
+	}
+
+	// Channels we only know mentions for (e.g. from the ready event) have no latest message
+	for cID, read := range s.unreads.read {
+		if _, exists := s.unreads.latest[cID]; !exists && len(read.MentionIDs) > 0 {
+			channels = append(channels, cID)
+		}
+	}
+
+	return channels
+}
+
 /*
 	API call updates
 	Used when (State.trackAPICalls or State.trackBulkAPICalls) is enabled
@@ -529,6 +924,54 @@ func (s *State) addEmoji(emoji *Emoji) {
 	s.emojis[emoji.ID] = emoji
 }
 
+func (s *State) addMessage(message *Message) {
+
+	if !s.trackAPICalls || !s.trackMessages || message == nil {
+		return
+	}
+
+	s.messagesMu.Lock()
+	defer s.messagesMu.Unlock()
+
+	s.messages.add(message, s.messagesPerChannel)
+}
+
+func (s *State) addMessages(messages []*Message) {
+
+	if !s.trackBulkAPICalls || !s.trackMessages || len(messages) == 0 {
+		return
+	}
+
+	s.messagesMu.Lock()
+	defer s.messagesMu.Unlock()
+
+	for _, message := range messages {
+		if message == nil {
+			continue
+		}
+
+		s.messages.add(message, s.messagesPerChannel)
+	}
+}
+
+// ackChannel is used by Session.MessageAck, rather than waiting for the server to echo a ChannelAck event
+func (s *State) ackChannel(cID, mID string) {
+
+	if !s.trackUnreads {
+		return
+	}
+
+	var uID string
+	if self := s.Self(); self != nil {
+		uID = self.ID
+	}
+
+	s.unreadsMu.Lock()
+	defer s.unreadsMu.Unlock()
+
+	s.unreads.ack(cID, uID, mID)
+}
+
 // StateConfig controls which entity caches the State maintains. Pass it to
 // Session.Open. The zero value tracks nothing.
 // Tracking is immutable once Session.Open() has connected.
@@ -539,6 +982,21 @@ type StateConfig struct {
 	TrackMembers  bool
 	TrackEmojis   bool
 
+	// TrackMessages caches the newest messages of every channel, filled from message events
+	// and, with TrackBulkAPICalls, from Session.ChannelMessages. It is opt-in, since it uses the most memory.
+	TrackMessages bool
+
+	// TrackVoiceStates tracks who is connected to which voice channel
+	TrackVoiceStates bool
+
+	// TrackUnreads tracks the last read message and unread mentions of every channel.
+	// With TrackChannels, channels that had unread messages before connecting are also known.
+	TrackUnreads bool
+
+	// MessagesPerChannel caps the message cache of each channel; the oldest messages are evicted first.
+	// Defaults to 100 if zero.
+	MessagesPerChannel int
+
 	// TrackAPICalls additionally updates the state from single API calls
 	TrackAPICalls bool
 
@@ -546,7 +1004,7 @@ type StateConfig struct {
 	TrackBulkAPICalls bool
 }
 
-// DefaultStateConfig returns a StateConfig that tracks everything.
+// DefaultStateConfig returns a StateConfig that tracks everything except messages.
 func DefaultStateConfig() StateConfig {
 	return StateConfig{
 		TrackUsers:        true,
@@ -554,6 +1012,8 @@ func DefaultStateConfig() StateConfig {
 		TrackChannels:     true,
 		TrackMembers:      true,
 		TrackEmojis:       true,
+		TrackVoiceStates:  true,
+		TrackUnreads:      true,
 		TrackAPICalls:     true,
 		TrackBulkAPICalls: true,
 	}
@@ -565,6 +1025,14 @@ func (s *State) applyConfig(c StateConfig) {
 	s.trackChannels = c.TrackChannels
 	s.trackMembers = c.TrackMembers
 	s.trackEmojis = c.TrackEmojis
+	s.trackMessages = c.TrackMessages
+	s.trackVoice = c.TrackVoiceStates
+	s.trackUnreads = c.TrackUnreads
+	s.messagesPerChannel = c.MessagesPerChannel
+	if s.messagesPerChannel <= 0 {
+		s.messagesPerChannel = defaultMessagesPerChannel
+	}
+
 	s.trackAPICalls = c.TrackAPICalls
 	s.trackBulkAPICalls = c.TrackBulkAPICalls
 }
@@ -577,12 +1045,24 @@ func newState() *State {
 		channels: make(map[string]*Channel),
 		members:  make(stateMembers),
 		emojis:   make(map[string]*Emoji),
+		messages: make(stateMessages),
+		voice:    newStateVoice(),
+		unreads:  newStateUnreads(),
+		logger:   slog.Default,
 	}
 
 	s.applyConfig(DefaultStateConfig())
 	return s
 }
 
+// unknownFields logs fields that an update event asked to clear, but the object doesn't know about.
+// This usually means the API has changed, and the library needs an update.
+func (s *State) unknownFields(object string, fields []string) {
+	if len(fields) > 0 {
+		s.logger().Warn("Unknown fields cleared", "object", object, "fields", fields)
+	}
+}
+
 // populate populates the state with the data from the ready event.
 // It will overwrite any existing data in the state.
 func (s *State) populate(ready *EventReady) {
@@ -629,6 +1109,9 @@ func (s *State) populate(ready *EventReady) {
 		s.membersMu.Unlock()
 	}
 
+	s.populateUnreads(ready)
+	s.populateVoice(ready)
+
 	if s.trackEmojis {
 		s.emojisMu.Lock()
 		s.emojis = make(map[string]*Emoji, len(ready.Emojis))
@@ -639,6 +1122,140 @@ func (s *State) populate(ready *EventReady) {
 	}
 }
 
+// populateUnreads rebuilds the unreads tracker from the ready event.
+func (s *State) populateUnreads(ready *EventReady) {
+
+	if !s.trackUnreads {
+		return
+	}
+
+	s.unreadsMu.Lock()
+	defer s.unreadsMu.Unlock()
+
+	s.unreads = newStateUnreads()
+	for _, unread := range ready.ChannelUnreads {
+		s.unreads.read[unread.ID.Channel] = &unread
+	}
+
+	// The ready event doesn't say who sent the last message; assume someone else did
+	for _, channel := range ready.Channels {
+		if channel.LastMessageID != nil {
+			s.unreads.latest[channel.ID] = *channel.LastMessageID
+		}
+	}
+}
+
+// populateVoice rebuilds the voice states from the ready event.
+func (s *State) populateVoice(ready *EventReady) {
+
+	if !s.trackVoice {
+		return
+	}
+
+	s.voiceMu.Lock()
+	defer s.voiceMu.Unlock()
+
+	s.voice = newStateVoice()
+	for _, channel := range ready.VoiceStates {
+		for _, participant := range channel.Participants {
+			s.voice.join(channel.ID, participant)
+		}
+	}
+}
+
+// reconcileMap makes cache match incoming without replacing the objects it already holds, so pointers
+// handed out before a reconnect stay current. The caller must hold the cache's lock.
+func reconcileMap[T any](cache map[string]*T, incoming []*T, id func(*T) string) {
+	seen := make(map[string]bool, len(incoming))
+
+	for _, object := range incoming {
+		key := id(object)
+		seen[key] = true
+
+		if existing := cache[key]; existing != nil {
+			*existing = *object
+		} else {
+			cache[key] = object
+		}
+	}
+
+	for key := range cache {
+		if !seen[key] {
+			delete(cache, key)
+		}
+	}
+}
+
+// reconcile is like populate, but updates the existing caches in place instead of rebuilding them.
+// It is used when a ready event arrives on a resumed connection.
+func (s *State) reconcile(ready *EventReady) {
+
+	var self *User
+	if len(ready.Users) > 0 {
+		// The last user in the ready event is the current user
+		self = ready.Users[len(ready.Users)-1]
+	}
+
+	if s.trackUsers {
+		s.usersMu.Lock()
+		reconcileMap(s.users, ready.Users, func(user *User) string { return user.ID })
+
+		// Keep self pointing at the cached object, like populate does
+		if self != nil {
+			self = s.users[self.ID]
+		}
+		s.usersMu.Unlock()
+	}
+
+	if self != nil {
+		s.self.Store(self)
+	}
+
+	if s.trackServers {
+		s.serversMu.Lock()
+		reconcileMap(s.servers, ready.Servers, func(server *Server) string { return server.ID })
+		s.serversMu.Unlock()
+	}
+
+	if s.trackChannels {
+		s.channelsMu.Lock()
+		reconcileMap(s.channels, ready.Channels, func(channel *Channel) string { return channel.ID })
+		s.channelsMu.Unlock()
+	}
+
+	if s.trackMembers {
+		s.membersMu.Lock()
+		seen := make(map[MemberCompositeID]bool, len(ready.Members))
+		for _, member := range ready.Members {
+			seen[member.ID] = true
+
+			if existing := s.members.get(member.ID.Server, member.ID.User); existing != nil {
+				*existing = *member
+			} else {
+				s.members.add(member)
+			}
+		}
+
+		for sID, members := range s.members {
+			for uID := range members {
+				if !seen[MemberCompositeID{Server: sID, User: uID}] {
+					delete(members, uID)
+				}
+			}
+		}
+		s.membersMu.Unlock()
+	}
+
+	s.populateUnreads(ready)
+	s.populateVoice(ready)
+
+	if s.trackEmojis {
+		s.emojisMu.Lock()
+		reconcileMap(s.emojis, ready.Emojis, func(emoji *Emoji) string { return emoji.ID })
+		s.emojisMu.Unlock()
+	}
+}
+
 // platformWipe removes a user from users, channels (dms and groups), and servers member lists.
 // It ignores all State.TrackX fields; the user is banned off the platform.
 func (s *State) platformWipe(event *EventUserPlatformWipe) {
@@ -665,6 +1282,13 @@ func (s *State) platformWipe(event *EventUserPlatformWipe) {
 	s.membersMu.Lock()
 	s.members.removeUser(event.UserID)
 	s.membersMu.Unlock()
+
+	// Disconnect from voice
+	s.voiceMu.Lock()
+	if cID, exists := s.voice.users[event.UserID]; exists {
+		s.voice.leave(cID, event.UserID)
+	}
+	s.voiceMu.Unlock()
 }
 
 func (s *State) updateServerRoleRanks(event *EventServerRoleRanksUpdate) {
@@ -678,14 +1302,14 @@ func (s *State) updateServerRoleRanks(event *EventServerRoleRanksUpdate) {
 
 	server := s.servers[event.ID]
 	if server == nil {
-		log.Printf("role ranks update for unknown server %s\n", event.ID)
+		s.logger().Warn("Role ranks update for unknown server", "server", event.ID)
 		return
 	}
 
 	for index, rID := range event.Ranks {
 		role, exists := server.Roles[rID]
 		if !exists {
-			log.Printf("role ranks update for unknown role %s in server %s\n", rID, event.ID)
+			s.logger().Warn("Role ranks update for unknown role", "server", event.ID, "role", rID)
 			continue
 		}
 
@@ -693,7 +1317,8 @@ func (s *State) updateServerRoleRanks(event *EventServerRoleRanksUpdate) {
 	}
 }
 
-func (s *State) updateServerRole(event *EventServerRoleUpdate) {
+// updateServerRole returns a copy of the role before the update (nil if it was created) and the updated role.
+func (s *State) updateServerRole(event *EventServerRoleUpdate) (before, after *ServerRole) {
 
 	if !s.trackServers {
 		return
@@ -704,7 +1329,7 @@ func (s *State) updateServerRole(event *EventServerRoleUpdate) {
 
 	server := s.servers[event.ID]
 	if server == nil {
-		log.Printf("update for role %s in unknown server %s\n", event.RoleID, event.ID)
+		s.logger().Warn("Role update in unknown server", "server", event.ID, "role", event.RoleID)
 		return
 	}
 
@@ -713,13 +1338,17 @@ func (s *State) updateServerRole(event *EventServerRoleUpdate) {
 		// Role was created
 		role = new(ServerRole)
 		server.Roles[event.RoleID] = role
+	} else {
+		snapshot := *role
+		before = &snapshot
 	}
 
 	role.update(event.Data)
-	role.clear(event.Clear)
+	s.unknownFields("ServerRole", role.clear(event.Clear))
+	return before, role
 }
 
-func (s *State) deleteServerRole(data *EventServerRoleDelete) {
+func (s *State) deleteServerRole(data *EventServerRoleDelete) (before *ServerRole) {
 
 	if !s.trackServers {
 		return
@@ -730,8 +1359,11 @@ func (s *State) deleteServerRole(data *EventServerRoleDelete) {
 
 	server := s.servers[data.ID]
 	if server != nil {
+		before = server.Roles[data.RoleID]
 		delete(server.Roles, data.RoleID)
 	}
+
+	return
 }
 
 func (s *State) createServerMember(data *EventServerMemberJoin) {
@@ -751,7 +1383,7 @@ func (s *State) createServerMember(data *EventServerMemberJoin) {
 	s.members.add(member)
 }
 
-func (s *State) deleteServerMember(data *EventServerMemberLeave) {
+func (s *State) deleteServerMember(data *EventServerMemberLeave) (before *ServerMember) {
 
 	/*
 		If the user that left is us, we left the server, thus:
@@ -765,7 +1397,7 @@ func (s *State) deleteServerMember(data *EventServerMemberLeave) {
 	self := s.Self()
 	if self != nil && data.User == self.ID {
 		s.deleteServer(&EventServerDelete{ID: data.ID})
-		return
+		return nil
 	}
 
 	if !s.trackMembers {
@@ -775,10 +1407,13 @@ func (s *State) deleteServerMember(data *EventServerMemberLeave) {
 	s.membersMu.Lock()
 	defer s.membersMu.Unlock()
 
+	before = s.members.get(data.ID, data.User)
 	s.members.remove(data.ID, data.User)
+	return
 }
 
-func (s *State) updateServerMember(event *EventServerMemberUpdate) {
+// updateServerMember returns a copy of the member before the update (nil if it wasn't cached) and the updated member.
+func (s *State) updateServerMember(event *EventServerMemberUpdate) (before, after *ServerMember) {
 
 	if !s.trackMembers {
 		return
@@ -787,10 +1422,16 @@ func (s *State) updateServerMember(event *EventServerMemberUpdate) {
 	s.membersMu.Lock()
 	defer s.membersMu.Unlock()
 
+	if member := s.members.get(event.ID.Server, event.ID.User); member != nil {
+		snapshot := *member
+		before = &snapshot
+	}
+
 	member := s.members.upsert(event.ID)
 
 	member.update(event.Data)
-	member.clear(event.Clear)
+	s.unknownFields("ServerMember", member.clear(event.Clear))
+	return before, member
 }
 
 func (s *State) createChannel(event *EventChannelCreate) {
@@ -816,7 +1457,7 @@ func (s *State) createChannel(event *EventChannelCreate) {
 
 	server := s.servers[*event.Server]
 	if server == nil {
-		log.Printf("channel %s created in unknown server %s\n", event.ID, *event.Server)
+		s.logger().Warn("Channel created in unknown server", "server", *event.Server, "channel", event.ID)
 		return
 	}
 
@@ -834,7 +1475,7 @@ func (s *State) addGroupParticipant(event *EventChannelGroupJoin) {
 
 	channel := s.channels[event.ID]
 	if channel == nil {
-		log.Printf("%s joined unknown group: %s\n", event.User, event.ID)
+		s.logger().Warn("User joined unknown group", "channel", event.ID, "user", event.User)
 		return
 	}
 
@@ -852,7 +1493,7 @@ func (s *State) removeGroupParticipant(event *EventChannelGroupLeave) {
 
 	channel := s.channels[event.ID]
 	if channel == nil {
-		log.Printf("%s left unknown group %s\n", event.User, event.ID)
+		s.logger().Warn("User left unknown group", "channel", event.ID, "user", event.User)
 		return
 	}
 
@@ -864,7 +1505,8 @@ func (s *State) removeGroupParticipant(event *EventChannelGroupLeave) {
 	}
 }
 
-func (s *State) updateChannel(event *EventChannelUpdate) {
+// updateChannel returns a copy of the channel before the update and the updated channel.
+func (s *State) updateChannel(event *EventChannelUpdate) (before, after *Channel) {
 
 	if !s.trackChannels {
 		return
@@ -875,15 +1517,36 @@ func (s *State) updateChannel(event *EventChannelUpdate) {
 
 	channel := s.channels[event.ID]
 	if channel == nil {
-		log.Printf("unknown channel updated %s\n", event.ID)
+		s.logger().Warn("Unknown channel updated", "channel", event.ID)
 		return
 	}
 
+	snapshot := *channel
+	before = &snapshot
 	channel.update(event.Data)
-	channel.clear(event.Clear)
+	s.unknownFields("Channel", channel.clear(event.Clear))
+	return before, channel
 }
 
-func (s *State) deleteChannel(event *EventChannelDelete) {
+func (s *State) deleteChannel(event *EventChannelDelete) (before *Channel) {
+
+	if s.trackMessages {
+		s.messagesMu.Lock()
+		s.messages.removeChannel(event.ID)
+		s.messagesMu.Unlock()
+	}
+
+	if s.trackVoice {
+		s.voiceMu.Lock()
+		s.voice.removeChannel(event.ID)
+		s.voiceMu.Unlock()
+	}
+
+	if s.trackUnreads {
+		s.unreadsMu.Lock()
+		s.unreads.removeChannel(event.ID)
+		s.unreadsMu.Unlock()
+	}
 
 	if !s.trackChannels {
 		return
@@ -893,20 +1556,20 @@ func (s *State) deleteChannel(event *EventChannelDelete) {
 	channel := s.channels[event.ID]
 	if channel == nil {
 		s.channelsMu.Unlock()
-		log.Printf("unknown channel deleted %s\n", event.ID)
-		return
+		s.logger().Warn("Unknown channel deleted", "channel", event.ID)
+		return nil
 	}
 
 	delete(s.channels, event.ID)
 	s.channelsMu.Unlock()
 
 	if !s.trackServers {
-		return
+		return channel
 	}
 
 	// If channel doesn't belong to a server, skip
 	if channel.Server == nil {
-		return
+		return channel
 	}
 
 	s.serversMu.Lock()
@@ -914,16 +1577,18 @@ func (s *State) deleteChannel(event *EventChannelDelete) {
 
 	server := s.servers[*channel.Server]
 	if server == nil {
-		log.Printf("channel %s deleted from unknown server %s\n", event.ID, *channel.Server)
-		return
+		s.logger().Warn("Channel deleted from unknown server", "server", *channel.Server, "channel", event.ID)
+		return channel
 	}
 
 	for i, cID := range server.Channels {
 		if cID == event.ID {
 			server.Channels = sliceRemoveIndex(server.Channels, i)
-			return
+			break
 		}
 	}
+
+	return channel
 }
 
 func (s *State) createServer(event *EventServerCreate) {
@@ -933,7 +1598,7 @@ func (s *State) createServer(event *EventServerCreate) {
 	}
 
 	if event.Server == nil {
-		log.Printf("State.createServer: server %s has no server data\n", event.ID)
+		s.logger().Warn("State.createServer: server has no server data", "server", event.ID)
 		return
 	}
 
@@ -946,7 +1611,7 @@ func (s *State) createServer(event *EventServerCreate) {
 
 		self := s.Self()
 		if self == nil {
-			log.Printf("State.createServer: s.TrackMembers & self is nil")
+			s.logger().Warn("State.createServer: s.TrackMembers & self is nil")
 			return
 		}
 
@@ -986,7 +1651,8 @@ func (s *State) createServer(event *EventServerCreate) {
 	}
 }
 
-func (s *State) updateServer(event *EventServerUpdate) {
+// updateServer returns a copy of the server before the update and the updated server.
+func (s *State) updateServer(event *EventServerUpdate) (before, after *Server) {
 
 	if !s.trackServers {
 		return
@@ -997,18 +1663,22 @@ func (s *State) updateServer(event *EventServerUpdate) {
 
 	server := s.servers[event.ID]
 	if server == nil {
-		log.Printf("unknown server update %s\n", event.ID)
+		s.logger().Warn("Unknown server updated", "server", event.ID)
 		return
 	}
 
+	snapshot := *server
+	before = &snapshot
 	server.update(event.Data)
-	server.clear(event.Clear)
+	s.unknownFields("Server", server.clear(event.Clear))
+	return before, server
 }
 
-func (s *State) deleteServer(event *EventServerDelete) {
+func (s *State) deleteServer(event *EventServerDelete) (before *Server) {
 
 	if s.trackServers {
 		s.serversMu.Lock()
+		before = s.servers[event.ID]
 		delete(s.servers, event.ID)
 		s.serversMu.Unlock()
 	}
@@ -1018,9 +1688,12 @@ func (s *State) deleteServer(event *EventServerDelete) {
 		s.members.removeServer(event.ID)
 		s.membersMu.Unlock()
 	}
+
+	return
 }
 
-func (s *State) updateUser(event *EventUserUpdate) {
+// updateUser returns a copy of the user before the update and the updated user.
+func (s *State) updateUser(event *EventUserUpdate) (before, after *User) {
 
 	if !s.trackUsers {
 		return
@@ -1032,12 +1705,15 @@ func (s *State) updateUser(event *EventUserUpdate) {
 	user := s.users[event.ID]
 	if user == nil {
 		// For self-bots, this will ignore a lot of events; maybe add more mechanisms for caching users?
-		// log.Printf("unknown user update %s\n", event.ID)
+		// s.logger().Warn("Unknown user updated", "user", event.ID)
 		return
 	}
 
+	snapshot := *user
+	before = &snapshot
 	user.update(event.Data)
-	user.clear(event.Clear)
+	s.unknownFields("User", user.clear(event.Clear))
+	return before, user
 }
 
 func (s *State) createEmoji(event *EventEmojiCreate) {
@@ -1052,7 +1728,7 @@ func (s *State) createEmoji(event *EventEmojiCreate) {
 	s.emojis[event.ID] = &event.Emoji
 }
 
-func (s *State) deleteEmoji(event *EventEmojiDelete) {
+func (s *State) deleteEmoji(event *EventEmojiDelete) (before *Emoji) {
 
 	if !s.trackEmojis {
 		return
@@ -1061,5 +1737,248 @@ func (s *State) deleteEmoji(event *EventEmojiDelete) {
 	s.emojisMu.Lock()
 	defer s.emojisMu.Unlock()
 
+	before = s.emojis[event.ID]
 	delete(s.emojis, event.ID)
+	return
+}
+
+func (s *State) createMessage(event *EventMessage) {
+
+	if !s.trackMessages {
+		return
+	}
+
+	s.messagesMu.Lock()
+	defer s.messagesMu.Unlock()
+
+	// Cache a copy: handlers hold the event, and the cached message changes with later events
+	message := event.Message
+	s.messages.add(&message, s.messagesPerChannel)
+}
+
+// Messages sent before we connected, or evicted since, are not cached; events for them are ignored silently.
+
+// updateMessage returns a copy of the message before the update and the updated message.
+func (s *State) updateMessage(event *EventMessageUpdate) (before, after *Message) {
+
+	if !s.trackMessages {
+		return
+	}
+
+	s.messagesMu.Lock()
+	defer s.messagesMu.Unlock()
+
+	message := s.messages.get(event.Channel, event.ID)
+	if message == nil {
+		return
+	}
+
+	snapshot := *message
+	before = &snapshot
+	message.update(event.Data)
+	s.unknownFields("Message", message.clear(event.Clear))
+	return before, message
+}
+
+func (s *State) appendMessage(event *EventMessageAppend) {
+
+	if !s.trackMessages {
+		return
+	}
+
+	s.messagesMu.Lock()
+	defer s.messagesMu.Unlock()
+
+	message := s.messages.get(event.Channel, event.ID)
+	if message == nil {
+		return
+	}
+
+	message.append(event.Append)
+}
+
+func (s *State) addMessageReaction(event *EventMessageReact) {
+
+	if !s.trackMessages {
+		return
+	}
+
+	s.messagesMu.Lock()
+	defer s.messagesMu.Unlock()
+
+	message := s.messages.get(event.ChannelID, event.ID)
+	if message == nil {
+		return
+	}
+
+	message.react(event.UserID, event.EmojiID)
+}
+
+func (s *State) removeMessageReaction(event *EventMessageUnreact) {
+
+	if !s.trackMessages {
+		return
+	}
+
+	s.messagesMu.Lock()
+	defer s.messagesMu.Unlock()
+
+	message := s.messages.get(event.ChannelID, event.ID)
+	if message == nil {
+		return
+	}
+
+	message.unreact(event.UserID, event.EmojiID)
+}
+
+func (s *State) clearMessageReaction(event *EventMessageRemoveReaction) {
+
+	if !s.trackMessages {
+		return
+	}
+
+	s.messagesMu.Lock()
+	defer s.messagesMu.Unlock()
+
+	message := s.messages.get(event.ChannelID, event.ID)
+	if message == nil {
+		return
+	}
+
+	message.clearReaction(event.EmojiID)
+}
+
+func (s *State) deleteMessage(event *EventMessageDelete) (before *Message) {
+
+	if !s.trackMessages {
+		return
+	}
+
+	s.messagesMu.Lock()
+	defer s.messagesMu.Unlock()
+
+	before = s.messages.get(event.Channel, event.ID)
+	s.messages.remove(event.Channel, event.ID)
+	return
+}
+
+// deleteMessages returns the messages that were cached, in no particular order.
+func (s *State) deleteMessages(event *EventBulkMessageDelete) (before []*Message) {
+
+	if !s.trackMessages {
+		return
+	}
+
+	s.messagesMu.Lock()
+	defer s.messagesMu.Unlock()
+
+	for _, mID := range event.IDs {
+		if message := s.messages.get(event.Channel, mID); message != nil {
+			before = append(before, message)
+			s.messages.remove(event.Channel, mID)
+		}
+	}
+
+	return
+}
+
+func (s *State) joinVoiceChannel(event *EventVoiceChannelJoin) {
+
+	if !s.trackVoice {
+		return
+	}
+
+	s.voiceMu.Lock()
+	defer s.voiceMu.Unlock()
+
+	// Cache a copy: handlers hold the event, and the cached state changes with later events
+	state := event.State
+	s.voice.join(event.ID, &state)
+}
+
+func (s *State) leaveVoiceChannel(event *EventVoiceChannelLeave) {
+
+	if !s.trackVoice {
+		return
+	}
+
+	s.voiceMu.Lock()
+	defer s.voiceMu.Unlock()
+
+	s.voice.leave(event.ID, event.User)
+}
+
+func (s *State) moveVoiceChannel(event *EventVoiceChannelMove) {
+
+	if !s.trackVoice {
+		return
+	}
+
+	s.voiceMu.Lock()
+	defer s.voiceMu.Unlock()
+
+	// A copy, like joinVoiceChannel; the state's ID is the user, set in case the server omits it
+	state := event.State
+	state.ID = event.User
+
+	s.voice.leave(event.From, event.User)
+	s.voice.join(event.To, &state)
+}
+
+func (s *State) updateVoiceState(event *EventUserVoiceStateUpdate) {
+
+	if !s.trackVoice {
+		return
+	}
+
+	s.voiceMu.Lock()
+	defer s.voiceMu.Unlock()
+
+	participant := s.voice.channels[event.ChannelID][event.ID]
+	if participant == nil {
+		s.logger().Warn("Voice state update in unknown voice channel", "channel", event.ChannelID, "user", event.ID)
+		return
+	}
+
+	participant.update(event.Data)
+}
+
+// addUnreadMessage records a new message as unread, and as a mention if it mentions the current user.
+// Our own messages never make a channel unread.
+func (s *State) addUnreadMessage(event *EventMessage) {
+
+	if !s.trackUnreads {
+		return
+	}
+
+	self := s.Self()
+	if self != nil && event.Author == self.ID {
+		return
+	}
+
+	s.unreadsMu.Lock()
+	defer s.unreadsMu.Unlock()
+
+	s.unreads.latest[event.Channel] = event.ID
+
+	if self != nil && slices.Contains(event.Mentions, self.ID) {
+		s.unreads.mention(event.Channel, self.ID, event.ID)
+	}
+}
+
+// readChannel handles acknowledgements from any of the current user's sessions.
+func (s *State) readChannel(event *EventChannelAck) {
+
+	if !s.trackUnreads {
+		return
+	}
+
+	if self := s.Self(); self != nil && event.User != self.ID {
+		return
+	}
+
+	s.unreadsMu.Lock()
+	defer s.unreadsMu.Unlock()
+
+	s.unreads.ack(event.ID, event.User, event.MessageID)
 }
@@ -0,0 +1,150 @@
+package revoltgo_test
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/sentinelb51/revoltgo"
+	"github.com/sentinelb51/revoltgo/revolttest"
+	"github.com/tinylib/msgp/msgp"
+)
+
+// openTracking opens a session caching messages and voice states, whose events are handled in order.
+func openTracking(t *testing.T, server *revolttest.Server) *revoltgo.Session {
+	t.Helper()
+
+	session := server.Session("token")
+	session.DispatchMode = revoltgo.DispatchInline
+
+	config := revoltgo.DefaultStateConfig()
+	config.TrackMessages = true
+	open(t, session, config)
+
+	return session
+}
+
+// push pushes events, failing the test on error.
+func push(t *testing.T, server *revolttest.Server, events ...msgp.Marshaler) {
+	t.Helper()
+
+	for _, event := range events {
+		if err := server.Push(event); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// TestMessageCacheIsCopy changes a message event in a handler; the cached message must not change with it.
+func TestMessageCacheIsCopy(t *testing.T) {
+	server := revolttest.NewServer()
+	defer server.Close()
+
+	session := openTracking(t, server)
+
+	handled := make(chan struct{})
+	revoltgo.AddHandler(session, func(_ *revoltgo.Session, e *revoltgo.EventMessage) {
+		e.Content = "changed by a handler"
+		close(handled)
+	})
+
+	push(t, server, &revoltgo.EventMessage{Message: revoltgo.Message{ID: "message", Channel: "general", Content: "hello"}})
+	wait(t, handled, "the message")
+
+	if message := session.State.Message("general", "message"); message == nil || message.Content != "hello" {
+		t.Fatalf("cached message is %+v", message)
+	}
+}
+
+// TestSnapshotBeforeUnchanged reacts to and appends to a message after it was updated; the Before of the update
+// must keep showing the message as it was.
+func TestSnapshotBeforeUnchanged(t *testing.T) {
+	server := revolttest.NewServer()
+	defer server.Close()
+
+	session := openTracking(t, server)
+
+	snapshots := make(chan *revoltgo.EventMessageUpdateSnapshot, 1)
+	revoltgo.AddHandler(session, func(_ *revoltgo.Session, e *revoltgo.EventMessageUpdateSnapshot) {
+		snapshots <- e
+	})
+
+	appended := make(chan struct{})
+	revoltgo.AddHandler(session, func(*revoltgo.Session, *revoltgo.EventMessageAppend) {
+		close(appended)
+	})
+
+	push(t, server,
+		&revoltgo.EventMessage{Message: revoltgo.Message{
+			ID:        "message",
+			Channel:   "general",
+			Content:   "hello",
+			Embeds:    []*revoltgo.MessageEmbed{{Title: "first"}},
+			Reactions: map[string][]string{"emoji": {"alice"}},
+		}},
+		&revoltgo.EventMessageUpdate{ID: "message", Channel: "general", Data: revoltgo.Message{Content: "edited"}},
+		&revoltgo.EventMessageReact{ID: "message", ChannelID: "general", UserID: "bob", EmojiID: "emoji"},
+		&revoltgo.EventMessageReact{ID: "message", ChannelID: "general", UserID: "bob", EmojiID: "other"},
+		&revoltgo.EventMessageAppend{ID: "message", Channel: "general", Append: revoltgo.Message{
+			Embeds: []*revoltgo.MessageEmbed{{Title: "second"}},
+		}},
+	)
+
+	wait(t, appended, "the embed to be appended")
+
+	var snapshot *revoltgo.EventMessageUpdateSnapshot
+	select {
+	case snapshot = <-snapshots:
+	default:
+		t.Fatal("no snapshot of the update")
+	}
+
+	before := snapshot.Before
+	if before == nil || before.Content != "hello" {
+		t.Fatalf("Before is %+v", before)
+	}
+
+	if reactions := before.Reactions; len(reactions) != 1 || !slices.Equal(reactions["emoji"], []string{"alice"}) {
+		t.Fatalf("Before changed with later reactions: %v", reactions)
+	}
+
+	if len(before.Embeds) != 1 {
+		t.Fatalf("Before changed with a later append: %d embeds", len(before.Embeds))
+	}
+
+	after := session.State.Message("general", "message")
+	if len(after.Reactions) != 2 || len(after.Reactions["emoji"]) != 2 || len(after.Embeds) != 2 {
+		t.Fatalf("cached message is %+v", after)
+	}
+}
+
+// TestVoiceMoveKeepsEvent moves a user whose voice state has no ID; the cache fills it in, but not in the event.
+func TestVoiceMoveKeepsEvent(t *testing.T) {
+	server := revolttest.NewServer()
+	defer server.Close()
+
+	session := openTracking(t, server)
+
+	moves := make(chan *revoltgo.EventVoiceChannelMove, 1)
+	revoltgo.AddHandler(session, func(_ *revoltgo.Session, e *revoltgo.EventVoiceChannelMove) {
+		moves <- e
+	})
+
+	push(t, server, &revoltgo.EventVoiceChannelMove{User: "alice", From: "lobby", To: "stage"})
+
+	var move *revoltgo.EventVoiceChannelMove
+	select {
+	case move = <-moves:
+	case <-time.After(timeout):
+		t.Fatal("timed out waiting for the move")
+	}
+
+	if move.State.ID != "" {
+		t.Fatalf("the cache changed the event's state ID to %q", move.State.ID)
+	}
+
+	participant := session.State.VoiceParticipant("stage", "alice")
+	if participant == nil || participant.ID != "alice" {
+		t.Fatalf("participant is %+v", participant)
+	}
+}
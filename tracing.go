@@ -0,0 +1,112 @@
+package revoltgo
+
+import (
+	"context"
+	"strconv"
+)
+
+// Tracer starts spans around the library's REST calls and event handlers, so they can be exported to a
+// tracing system. Set it on Session.Tracer before opening the session; see the revoltotel package for an
+// OpenTelemetry adapter. The library itself depends on no tracing SDK.
+//
+// REST calls are traced as a span per request, with child spans for each attempt's ratelimit wait,
+// send, and decode. Events are traced as a span per dispatch, with a child span per handler.
+// Requests are described by their route template (e.g. "/webhooks/%s/%s"), never their URL, so that secrets
+// such as webhook tokens don't reach the tracing system.
+// To trace a REST call as part of handling an event, pass the event's Context to the ...Ctx method:
+//
+//	revoltgo.AddHandler(session, func(s *revoltgo.Session, e *revoltgo.EventMessage) {
+//		_, _ = s.ChannelMessageSendCtx(e.Context(), e.Channel, revoltgo.MessageSend{Content: "pong"})
+//	})
+type Tracer interface {
+	// Start starts a span as a child of the span in ctx, if any, and returns a context carrying the new span
+	Start(ctx context.Context, name string, attributes ...Attribute) (context.Context, Span)
+}
+
+// Span is a traced operation, started by a Tracer. End is always called exactly once.
+type Span interface {
+	SetAttributes(attributes ...Attribute)
+	RecordError(err error)
+	End()
+}
+
+// Attribute is a key-value pair describing a Span. Value is a string, int, int64, float64 or bool.
+type Attribute struct {
+	Key   string
+	Value any
+}
+
+// Attribute keys used by the library
+const (
+	AttributeHTTPMethod     = "http.request.method"
+	AttributeHTTPRoute      = "http.route"
+	AttributeHTTPStatusCode = "http.response.status_code"
+	AttributeHTTPAttempt    = "http.request.resend_count"
+	AttributeEventType      = "revolt.event.type"
+	AttributeEventHandler   = "revolt.event.handler"
+	AttributeServerID       = "revolt.server.id"
+	AttributeChannelID      = "revolt.channel.id"
+	AttributeMessageID      = "revolt.message.id"
+	AttributeUserID         = "revolt.user.id"
+	AttributeRoleID         = "revolt.role.id"
+)
+
+type noopTracer struct{}
+
+func (noopTracer) Start(ctx context.Context, _ string, _ ...Attribute) (context.Context, Span) {
+	return ctx, noopSpan{}
+}
+
+type noopSpan struct{}
+
+func (noopSpan) SetAttributes(...Attribute) {}
+func (noopSpan) RecordError(error)          {}
+func (noopSpan) End()                       {}
+
+// tracer returns the Session's Tracer, falling back to one that records nothing
+func (s *Session) tracer() Tracer {
+	if s.Tracer != nil {
+		return s.Tracer
+	}
+
+	return noopTracer{}
+}
+
+// endSpan records err, if any, and ends the span.
+func endSpan(span Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+	}
+
+	span.End()
+}
+
+// handlerAttribute names a handler in its span: "default" for the library's handler, and the
+// registration order for user handlers.
+func handlerAttribute(index int) Attribute {
+	if index < 0 {
+		return Attribute{Key: AttributeEventHandler, Value: "default"}
+	}
+
+	return Attribute{Key: AttributeEventHandler, Value: strconv.Itoa(index)}
+}
+
+// eventAttributes describes an event, for its span.
+func eventAttributes(eventType string, event any) []Attribute {
+	attributes := make([]Attribute, 1, 5)
+	attributes[0] = Attribute{Key: AttributeEventType, Value: eventType}
+
+	server, channel, message, user := eventIDs(event)
+	for _, id := range [...]Attribute{
+		{Key: AttributeServerID, Value: server},
+		{Key: AttributeChannelID, Value: channel},
+		{Key: AttributeMessageID, Value: message},
+		{Key: AttributeUserID, Value: user},
+	} {
+		if id.Value != "" {
+			attributes = append(attributes, id)
+		}
+	}
+
+	return attributes
+}
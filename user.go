@@ -48,6 +48,15 @@ func (u *User) AvatarURL(size string) string {
 	return u.Avatar.URL(size)
 }
 
+// UserAvatarURL is like User.AvatarURL, but on the session's instance. Default avatars are absolute URLs on its API.
+func (s *Session) UserAvatarURL(user *User, size string) string {
+	if user.Avatar == nil {
+		return s.HTTP.URLs().API + EndpointUserDefaultAvatar(user.ID)
+	}
+
+	return s.FileURL(*user.Avatar, size)
+}
+
 func (u *User) update(data PartialUser) {
 	if data.Username != nil {
 		u.Username = *data.Username
@@ -98,29 +107,38 @@ func (u *User) update(data PartialUser) {
 	}
 }
 
-func (u *User) clear(fields []string) {
+func (u *User) clear(fields []string) (unknown []string) {
 	for _, field := range fields {
 		switch field {
+		// Nested values are replaced rather than edited, since snapshots handed to handlers may share them
 		case "ProfileContent":
 			if u.Profile != nil {
-				u.Profile.Content = ""
+				profile := *u.Profile
+				profile.Content = ""
+				u.Profile = &profile
 			}
 		case "ProfileBackground":
 			if u.Profile != nil {
-				u.Profile.Background = nil
+				profile := *u.Profile
+				profile.Background = nil
+				u.Profile = &profile
 			}
 		case "StatusText":
 			if u.Status != nil {
-				u.Status.Text = ""
+				status := *u.Status
+				status.Text = ""
+				u.Status = &status
 			}
 		case "Avatar":
 			u.Avatar = nil
 		case "DisplayName":
 			u.DisplayName = nil
 		default:
-			fmt.Printf("User.Clear(): unknown field %s\n", field)
+			unknown = append(unknown, field)
 		}
 	}
+
+	return unknown
 }
 
 type PartialUser struct {
@@ -193,6 +211,28 @@ type UserVoiceState struct {
 	Camera        bool       `msg:"camera" json:"camera,omitempty"`
 }
 
+func (v *UserVoiceState) update(data PartialUserVoiceState) {
+	if data.JoinedAt != nil {
+		v.JoinedAt = data.JoinedAt
+	}
+
+	if data.IsReceiving != nil {
+		v.IsReceiving = *data.IsReceiving
+	}
+
+	if data.IsPublishing != nil {
+		v.IsPublishing = *data.IsPublishing
+	}
+
+	if data.Screensharing != nil {
+		v.Screensharing = *data.Screensharing
+	}
+
+	if data.Camera != nil {
+		v.Camera = *data.Camera
+	}
+}
+
 type PartialUserVoiceState struct {
 	ID            *string    `msg:"_id" json:"_id,omitempty"`
 	JoinedAt      *time.Time `msg:"joined_at" json:"joined_at,omitempty"`
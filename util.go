@@ -1,9 +1,12 @@
 package revoltgo
 
 import (
+	"context"
 	"fmt"
+	"io"
 	"net/url"
 	"strings"
+	"time"
 )
 
 // WebhookFromURL extracts the webhook ID and token from a full webhook URL, for example:
@@ -56,28 +59,56 @@ func mustParseURL(raw string) *url.URL {
 	return u
 }
 
-func validateBaseURL(newURL string) (u *url.URL, err error) {
-	newURL = strings.TrimSuffix(strings.TrimSpace(newURL), "/")
+// sleepContext pauses for the given duration, returning early with ctx.Err() if ctx is done first.
+func sleepContext(ctx context.Context, duration time.Duration) error {
+	timer := time.NewTimer(duration)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// contextReader fails reads once ctx is done, so long copies (e.g. uploads) can be abandoned mid-stream.
+type contextReader struct {
+	ctx    context.Context
+	reader io.Reader
+}
+
+func (r contextReader) Read(p []byte) (int, error) {
+	if err := r.ctx.Err(); err != nil {
+		return 0, err
+	}
+	return r.reader.Read(p)
+}
+
+// validateBaseURL checks a base URL for SetBaseURL and SetCDNURL, and strips its trailing slashes.
+// Unless insecure URLs are allowed (see AllowInsecureBaseURLs), it must use HTTPS and a domain with a TLD.
+func validateBaseURL(newURL string, insecure bool) (u *url.URL, err error) {
+	newURL = strings.TrimRight(strings.TrimSpace(newURL), "/")
 
 	u, err = url.Parse(newURL)
 	if err != nil {
 		return nil, fmt.Errorf("invalid base URL: %w", err)
 	}
 
-	if u.Scheme != "https" {
-		return nil, fmt.Errorf("base URL must use HTTPS")
-	}
-
-	if u.Path != "" {
-		return nil, fmt.Errorf("base URL must not have a path (trailing /)")
+	if u.Scheme != "https" && !(insecure && u.Scheme == "http") {
+		return nil, fmt.Errorf("base URL must use HTTPS (or HTTP with AllowInsecureBaseURLs)")
 	}
 
 	if u.Host == "" {
 		return nil, fmt.Errorf("base URL must have a host")
 	}
 
-	if strings.Count(u.Hostname(), ".") < 1 {
-		return nil, fmt.Errorf("base URL must have a domain and TLD")
+	if u.User != nil || u.RawQuery != "" || u.Fragment != "" {
+		return nil, fmt.Errorf("base URL must not have credentials, a query or a fragment")
+	}
+
+	if !insecure && strings.Count(u.Hostname(), ".") < 1 {
+		return nil, fmt.Errorf("base URL must have a domain and TLD (or use AllowInsecureBaseURLs)")
 	}
 
 	return
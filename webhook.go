@@ -1,7 +1,5 @@
 package revoltgo
 
-import "log"
-
 //go:generate msgp -tests=false -io=false
 
 type WebhookRemoveField string
@@ -49,15 +47,17 @@ func (w *Webhook) update(data PartialWebhook) {
 	}
 }
 
-func (w *Webhook) clear(fields []string) {
+func (w *Webhook) clear(fields []string) (unknown []string) {
 	for _, field := range fields {
 		switch field {
 		case "Avatar":
 			w.Avatar = nil
 		default:
-			log.Printf("Webhook.clear(): unknown field %s", field)
+			unknown = append(unknown, field)
 		}
 	}
+
+	return unknown
 }
 
 type PartialWebhook struct {
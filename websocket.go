@@ -5,7 +5,8 @@ import (
 	"context"
 	"encoding/binary"
 	"errors"
-	"log"
+	"fmt"
+	"net/url"
 	"runtime"
 	"strings"
 	"sync"
@@ -16,11 +17,6 @@ import (
 	"github.com/tinylib/msgp/msgp"
 )
 
-func init() {
-	log.SetFlags(log.LstdFlags | log.Lshortfile)
-	log.SetPrefix("[R-GO] ")
-}
-
 type Websocket struct {
 	url     string
 	session *Session
@@ -35,30 +31,54 @@ type Websocket struct {
 	heartbeatLastSent time.Time
 	heartbeatLastAck  time.Time
 
+	// state is the ConnectionState; stateChanged is closed (and replaced) whenever it changes
+	stateMu      sync.Mutex
+	state        ConnectionState
+	stateChanged chan struct{}
+
+	// ready is set once an EventReady has populated the State, so later connections can resume
+	ready atomic.Bool
+	// resuming is set while the current connection is a resumed one
+	resuming       atomic.Bool
+	disconnectedAt time.Time
+
+	// dispatcher runs user handlers as Session.DispatchMode decides
+	dispatcher *dispatcher
+	// readers bounds the frames processed at once under DispatchParallel while recording, see OnMessage
+	readers chan struct{}
+
 	/* Configurable options */
 
 	// Interval between sending heartbeats. Lower values update the latency faster.
 	// Values too high (>=100 seconds) may cause Cloudflare to drop the connection
 	HeartbeatInterval time.Duration
 
-	Debug             bool                   // Prints sending (not a typo) and received websocket messages
+	Debug             bool                   // Logs sent and received websocket messages at slog.LevelDebug
 	ShouldReconnect   bool                   // Whether the websocket should attempt to reconnect on disconnection
-	ReconnectInterval time.Duration          // Interval between reconnecting, if connection fails
+	ReconnectPolicy   ReconnectPolicy        // Backoff between reconnection attempts, and when to give up
 	CustomCompression *gws.PermessageDeflate // Defines a custom compression algorithm for the Websocket.
+
+	// Resume makes reconnections keep the State instead of receiving a new EventReady. Events sent while
+	// disconnected are never replayed, so a resumed State may be stale; re-fetch whatever must be exact on
+	// EventResumed. Off by default, so that every reconnection rebuilds the State from a fresh EventReady
+	Resume bool
 }
 
 // newWebsocket constructs a websocket wrapper.
 func newWebsocket(session *Session, url string) *Websocket {
 	ctx, cancel := context.WithCancel(context.Background())
 	return &Websocket{
-		url:     url,
-		session: session,
-		ctx:     ctx,
-		cancel:  cancel,
+		url:          url,
+		session:      session,
+		ctx:          ctx,
+		cancel:       cancel,
+		stateChanged: make(chan struct{}),
+		dispatcher:   newDispatcher(ctx, session.DispatchMode, session.DispatchWorkers),
+		readers:      make(chan struct{}, runtime.NumCPU()),
 
 		ShouldReconnect:   true,
 		HeartbeatInterval: 30 * time.Second,
-		ReconnectInterval: 5 * time.Second,
+		ReconnectPolicy:   DefaultReconnectPolicy(),
 		// CustomCompression; not defined as the websocket doesn't support it yet
 	}
 }
@@ -66,10 +86,49 @@ func newWebsocket(session *Session, url string) *Websocket {
 func (ws *Websocket) printDebugData(data []byte) {
 	var buf bytes.Buffer
 	if _, err := msgp.CopyToJSON(&buf, bytes.NewReader(data)); err != nil {
-		log.Printf("Failed to convert msgpack to JSON for debug: %s\n", err)
+		ws.session.logger().Debug("Failed to convert msgpack to JSON for debug", "err", err)
+		return
+	}
+	ws.session.logger().Debug("[WS/RX]", "payload", buf.String())
+}
+
+// ConnectionState returns the current state of the connection
+func (ws *Websocket) ConnectionState() ConnectionState {
+	ws.stateMu.Lock()
+	defer ws.stateMu.Unlock()
+	return ws.state
+}
+
+// setState moves the connection to a new state, waking anyone waiting on it and dispatching EventConnectionStateChange.
+func (ws *Websocket) setState(to ConnectionState) {
+	ws.stateMu.Lock()
+	from := ws.state
+	if from == to {
+		ws.stateMu.Unlock()
 		return
 	}
-	log.Printf("[WS/RX]: %s\n", buf.String())
+
+	ws.state = to
+	close(ws.stateChanged)
+	ws.stateChanged = make(chan struct{})
+	ws.stateMu.Unlock()
+
+	if ws.Debug {
+		ws.session.logger().Debug("[WS/STATE]", "from", from, "to", to)
+	}
+
+	ws.session.dispatch("ConnectionStateChange", &EventConnectionStateChange{
+		Event: Event{Type: "ConnectionStateChange"},
+		From:  from,
+		To:    to,
+	})
+}
+
+// waitState returns the current state, and a channel that is closed when it changes.
+func (ws *Websocket) waitState() (ConnectionState, <-chan struct{}) {
+	ws.stateMu.Lock()
+	defer ws.stateMu.Unlock()
+	return ws.state, ws.stateChanged
 }
 
 func (ws *Websocket) IsConnected() bool {
@@ -104,21 +163,33 @@ func (ws *Websocket) Uptime() time.Duration {
 
 // connect dials the gateway. The caller decides what a failure means:
 // Open reports it, reconnectLoop retries it.
-func (ws *Websocket) connect() error {
-	ws.mu.Lock()
-	defer ws.mu.Unlock()
+// If resume is set, the server is asked to skip the EventReady, and the State is kept as it is.
+func (ws *Websocket) connect(resume bool) error {
 
 	// If we are already shutting down, do not reconnect
 	if ws.ctx.Err() != nil {
 		return ws.ctx.Err()
 	}
 
-	url, _, _ := strings.Cut(ws.url, "?")
-	log.Printf("Connecting to %s...\n", url)
+	// Set before locking, since handlers of the state change may use the Websocket
+	ws.setState(ConnectionStateConnecting)
+
+	ws.mu.Lock()
+	defer ws.mu.Unlock()
+
+	address := ws.url
+	if resume {
+		address = resumeURL(address)
+	}
+
+	ws.resuming.Store(resume)
+
+	host, _, _ := strings.Cut(address, "?")
+	ws.session.logger().Info("Connecting...", "host", host)
 
 	options := &gws.ClientOption{
-		Addr:             ws.url,
-		ParallelEnabled:  true,
+		Addr:             address,
+		ParallelEnabled:  ws.dispatcher.mode == DispatchParallel && ws.session.Recorder == nil,
 		ParallelGolimit:  runtime.NumCPU(),
 		CheckUtf8Enabled: false,
 	}
@@ -143,24 +214,96 @@ func (ws *Websocket) connect() error {
 }
 
 // reconnectLoop retries until the connection succeeds, the session is closed,
-// or reconnects are disabled.
+// reconnects are disabled, or the ReconnectPolicy gives up.
 func (ws *Websocket) reconnectLoop() {
-	for ws.ShouldReconnect {
+	policy := ws.ReconnectPolicy
+
+	var err error
+	for attempt := 1; ws.ShouldReconnect; attempt++ {
+		if policy.MaxAttempts > 0 && attempt > policy.MaxAttempts {
+			ws.giveUp(fmt.Errorf("reconnect: gave up after %d attempts: %w", policy.MaxAttempts, err))
+			return
+		}
+
+		delay := policy.delay(attempt)
+		if ws.Debug {
+			ws.session.logger().Debug("[WS/RECONNECT]", "attempt", attempt, "delay", delay)
+		}
+
 		select {
 		case <-ws.ctx.Done():
+			ws.setState(ConnectionStateClosed)
 			return
-		case <-time.After(ws.ReconnectInterval):
+		case <-time.After(delay):
 		}
 
-		log.Printf("Re-connecting...")
+		ws.session.logger().Info("Re-connecting...", "attempt", attempt)
+		ws.session.metrics().IncReconnect()
+		ws.session.dispatch("Reconnecting", &EventReconnecting{Event: Event{Type: "Reconnecting"}, Attempt: attempt})
 
-		err := ws.connect()
+		// Only resume if there is a State to keep
+		err = ws.connect(ws.Resume && ws.ready.Load())
 		if err == nil {
 			return
 		}
 
-		log.Printf("Connection failed: %s\n", err)
+		ws.session.logger().Warn("Connection failed", "attempt", attempt, "err", err)
+		ws.setState(ConnectionStateReconnecting)
+	}
+
+	ws.setState(ConnectionStateClosed)
+}
+
+// giveUp stops reconnecting for good, and tells ReconnectPolicy.OnGiveUp why.
+func (ws *Websocket) giveUp(err error) {
+	ws.session.logger().Error("Not reconnecting", "err", err)
+
+	// Cancelling stops OnClose from scheduling another reconnect
+	_ = ws.WriteClose()
+	ws.setState(ConnectionStateClosed)
+
+	if ws.ReconnectPolicy.OnGiveUp != nil {
+		ws.ReconnectPolicy.OnGiveUp(err)
+	}
+}
+
+// drop closes the current connection without closing the Websocket, so that it reconnects.
+func (ws *Websocket) drop() {
+	ws.mu.RLock()
+	defer ws.mu.RUnlock()
+
+	if ws.conn != nil {
+		_ = ws.conn.WriteClose(1000, nil) // Fires OnClose: handle reconnection logic
+	}
+}
+
+// resumeURL sets the undocumented "reconnect" query parameter, which makes the server skip the EventReady.
+func resumeURL(raw string) string {
+	address, err := url.Parse(raw)
+	if err != nil {
+		return raw
+	}
+
+	query := address.Query()
+	query.Set("reconnect", "true")
+	address.RawQuery = query.Encode()
+	return address.String()
+}
+
+// resumed is called once a connection has authenticated. It reports whether the connection was resumed.
+func (ws *Websocket) resumed() bool {
+	if !ws.resuming.Load() {
+		return false
 	}
+
+	ws.mu.RLock()
+	downtime := time.Since(ws.disconnectedAt)
+	ws.mu.RUnlock()
+
+	ws.session.logger().Info("Resumed", "downtime", downtime.Round(time.Millisecond))
+	ws.setState(ConnectionStateReady)
+	ws.session.dispatch("Resumed", &EventResumed{Event: Event{Type: "Resumed"}, Downtime: downtime})
+	return true
 }
 
 func (ws *Websocket) heartbeatLoop(original *gws.Conn) {
@@ -186,7 +329,7 @@ func (ws *Websocket) heartbeatLoop(original *gws.Conn) {
 			binary.LittleEndian.PutUint64(payload, uint64(count))
 
 			if err := current.WritePing(payload); err != nil {
-				log.Printf("Heartbeat failed: %s\n", err)
+				ws.session.logger().Warn("Heartbeat failed", "err", err)
 				_ = current.WriteClose(1000, nil) // Fires OnClose: handle reconnection logic
 				return
 			}
@@ -199,11 +342,12 @@ func (ws *Websocket) heartbeatLoop(original *gws.Conn) {
 }
 
 func (ws *Websocket) OnOpen(socket *gws.Conn) {
-	log.Printf("Resolved: %s\n", socket.RemoteAddr())
+	ws.session.logger().Debug("Resolved", "addr", socket.RemoteAddr())
 	atomic.StoreInt64(&ws.heartbeatCount, 0)
+	ws.setState(ConnectionStateAuthenticating)
 
 	if err := socket.SetDeadline(time.Now().Add(WebsocketKeepAlivePeriod * 2)); err != nil {
-		log.Printf("Set deadline failed: %s\n", err)
+		ws.session.logger().Warn("Set deadline failed", "err", err)
 		_ = socket.WriteClose(1000, nil) // Fires OnClose: handle reconnection logic
 		return
 	}
@@ -214,10 +358,14 @@ func (ws *Websocket) OnOpen(socket *gws.Conn) {
 func (ws *Websocket) OnClose(_ *gws.Conn, err error) {
 	ws.mu.Lock()
 	ws.conn = nil
+	ws.disconnectedAt = time.Now()
 	ws.mu.Unlock()
 
+	ws.session.dispatch("Disconnected", &EventDisconnected{Event: Event{Type: "Disconnected"}, Err: err})
+
 	if err == nil {
-		log.Println("Connection closed gracefully")
+		ws.session.logger().Info("Connection closed gracefully")
+		ws.setState(ConnectionStateClosed)
 		return
 	}
 
@@ -227,14 +375,23 @@ func (ws *Websocket) OnClose(_ *gws.Conn, err error) {
 	*/
 
 	if closeErr, ok := errors.AsType[*gws.CloseError](err); ok {
-		log.Printf("Connection closed with code %d: %s\n", closeErr.Code, err)
+		ws.session.logger().Warn("Connection closed", "code", closeErr.Code, "err", err)
+
+		if ws.ReconnectPolicy.fatalCloseCode(closeErr.Code) && ws.ctx.Err() == nil {
+			ws.giveUp(fmt.Errorf("reconnect: fatal close code %d: %w", closeErr.Code, err))
+			return
+		}
 	} else {
-		log.Printf("Connection closed with error: %s\n", err)
+		ws.session.logger().Warn("Connection closed with error", "err", err)
 	}
 
 	if ws.ShouldReconnect && ws.ctx.Err() == nil {
+		ws.setState(ConnectionStateReconnecting)
 		go ws.reconnectLoop()
+		return
 	}
+
+	ws.setState(ConnectionStateClosed)
 }
 
 func (ws *Websocket) OnPong(socket *gws.Conn, payload []byte) {
@@ -246,25 +403,57 @@ func (ws *Websocket) OnPong(socket *gws.Conn, payload []byte) {
 	current := atomic.LoadInt64(&ws.heartbeatCount)
 
 	if count != current {
-		log.Printf("Heartbeat mismatch: %d != %d\n", count, current)
+		ws.session.logger().Warn("Heartbeat mismatch", "received", count, "expected", current)
 		return
 	}
 
 	ws.mu.Lock()
 	ws.heartbeatLastAck = time.Now()
+	latency := ws.heartbeatLastAck.Sub(ws.heartbeatLastSent)
 	ws.mu.Unlock()
 
+	ws.session.metrics().ObserveHeartbeat(latency)
+
 	atomic.AddInt64(&ws.heartbeatCount, 1)
 	_ = socket.SetDeadline(time.Now().Add(ws.HeartbeatInterval * 2))
 }
 
 func (ws *Websocket) OnPing(_ *gws.Conn, payload []byte) {
-	log.Printf("Received unexpected ping: %s\n", string(payload))
+	ws.session.logger().Debug("Received unexpected ping", "payload", string(payload))
 }
 
 func (ws *Websocket) OnMessage(_ *gws.Conn, message *gws.Message) {
 
 	data := message.Data.Bytes()
+
+	recorder := ws.session.Recorder
+	if recorder == nil {
+		ws.process(message)
+		return
+	}
+
+	if err := recorder.Record(data); err != nil {
+		ws.session.logger().Warn("Recording frame failed", "err", err)
+	}
+
+	// gws reads one frame at a time while recording, so that frames are recorded in the order received;
+	// DispatchParallel then processes them in parallel from here, as many at once as gws would
+	if ws.dispatcher.mode != DispatchParallel {
+		ws.process(message)
+		return
+	}
+
+	ws.readers <- struct{}{}
+	go func() {
+		defer func() { <-ws.readers }()
+		ws.process(message)
+	}()
+}
+
+// process handles a received frame, then releases it.
+func (ws *Websocket) process(message *gws.Message) {
+	data := message.Data.Bytes()
+
 	ws.handle(data)
 
 	if ws.Debug {
@@ -272,7 +461,7 @@ func (ws *Websocket) OnMessage(_ *gws.Conn, message *gws.Message) {
 	}
 
 	if err := message.Close(); err != nil {
-		log.Printf("OnMessage Close() error: %s\n", err)
+		ws.session.logger().Warn("OnMessage Close() error", "err", err)
 	}
 }
 
@@ -285,7 +474,7 @@ func (ws *Websocket) WriteMessage(opcode gws.Opcode, payload []byte) error {
 	}
 
 	if ws.Debug {
-		log.Printf("[WS/TX]: %s\n", string(payload))
+		ws.session.logger().Debug("[WS/TX]", "payload", string(payload))
 	}
 
 	return ws.conn.WriteMessage(opcode, payload)
@@ -308,12 +497,17 @@ func (ws *Websocket) WriteClose() error {
 
 func (ws *Websocket) handle(raw []byte) {
 
+	metrics := ws.session.metrics()
+
 	eventType, err := eventTypeFromMSGP(raw)
 	if err != nil {
-		log.Printf("event type detection failed: %s\n", err)
+		metrics.IncDecodeFailure("")
+		ws.session.logger().Error("Event type detection failed", "err", err)
 		return
 	}
 
+	metrics.IncEventReceived(string(eventType))
+
 	// Load the immutable handler snapshot lock-free. Writers swap in a fresh
 	// snapshot via copy-on-write, so this read never blocks or tears.
 	handlers := ws.session.handlers.Load()
@@ -322,12 +516,14 @@ func (ws *Websocket) handle(raw []byte) {
 
 	// No one is listening for this event; drop it before paying the decode cost.
 	if defaultHandler == nil && len(userHandlers) == 0 {
+		metrics.IncEventDropped(string(eventType))
 		return
 	}
 
 	constructor, found := eventConstructors[string(eventType)]
 	if !found {
-		log.Println("Unknown event type:", string(eventType))
+		metrics.IncEventDropped(string(eventType))
+		ws.session.logger().Warn("Unknown event type", "event", string(eventType))
 		return
 	}
 
@@ -335,7 +531,13 @@ func (ws *Websocket) handle(raw []byte) {
 	// compile-time error in eventConstructors rather than a runtime check here.
 	event := constructor()
 	if _, err = event.UnmarshalMsg(raw); err != nil {
-		log.Printf("Failed to unmarshal event %T: %s\n", event, err)
+		metrics.IncDecodeFailure(string(eventType))
+		ws.session.logger().Error("Failed to unmarshal event", "event", string(eventType), "err", err)
+		return
+	}
+
+	if ws.session.Tracer != nil {
+		ws.handleTraced(string(eventType), event, defaultHandler, userHandlers)
 		return
 	}
 
@@ -344,7 +546,40 @@ func (ws *Websocket) handle(raw []byte) {
 		defaultHandler(ws.session, event)
 	}
 
-	for _, h := range userHandlers {
-		h(ws.session, event)
+	if len(userHandlers) > 0 {
+		ws.dispatcher.run(event, func() {
+			for _, h := range userHandlers {
+				h.call(ws.session, event)
+			}
+		})
+	}
+}
+
+// handleTraced is handle's dispatch, with a span for the event and a child span for every handler.
+// The event's Context carries the event span, so REST calls made by handlers become part of its trace.
+func (ws *Websocket) handleTraced(eventType string, event any, defaultHandler func(*Session, any), userHandlers []*userHandler) {
+	tracer := ws.session.Tracer
+
+	ctx, span := tracer.Start(context.Background(), "revoltgo.event "+eventType, eventAttributes(eventType, event)...)
+
+	if traced, ok := event.(interface{ setContext(context.Context) }); ok {
+		traced.setContext(ctx)
+	}
+
+	if defaultHandler != nil {
+		_, handlerSpan := tracer.Start(ctx, "revoltgo.handler", handlerAttribute(-1))
+		defaultHandler(ws.session, event)
+		handlerSpan.End()
 	}
+
+	// The event span lasts until its user handlers have run, wherever the dispatcher runs them
+	ws.dispatcher.run(event, func() {
+		defer span.End()
+
+		for i, h := range userHandlers {
+			_, handlerSpan := tracer.Start(ctx, "revoltgo.handler", handlerAttribute(i))
+			h.call(ws.session, event)
+			handlerSpan.End()
+		}
+	})
 }